@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"hackathon-backend/usecase"
+	"log"
+	"net/http"
+
+	"firebase.google.com/go/auth"
+)
+
+type AccountController struct {
+	BaseController
+	Usecase *usecase.AccountUsecase
+}
+
+func NewAccountController(u *usecase.AccountUsecase, auth *auth.Client) *AccountController {
+	return &AccountController{
+		BaseController: BaseController{AuthClient: auth},
+		Usecase:        u,
+	}
+}
+
+// HandleExport: GET /users/me/export
+// 本人のデータ一式をJSONファイルとしてダウンロードさせます
+func (c *AccountController) HandleExport(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, err := c.verifyToken(r)
+	if err != nil {
+		c.respondError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	res, err := c.Usecase.Export(firebaseUID)
+	if err != nil {
+		c.respondError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	filename := fmt.Sprintf("export-%s.json", res.ExportedAt.Format("20060102-150405"))
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.respondJSON(w, http.StatusOK, res)
+}
+
+// HandleDelete: DELETE /users/me
+// 退会します。取引中 (売れてから14日以内) の商品があれば 409 と商品IDを、利用停止・BAN中なら 403 を返します
+func (c *AccountController) HandleDelete(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, err := c.verifyToken(r)
+	if err != nil {
+		c.respondError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	err = c.Usecase.DeleteAccount(r.Context(), firebaseUID)
+	var inProgress *usecase.TradesInProgressError
+	if errors.As(err, &inProgress) {
+		c.respondJSON(w, http.StatusConflict, map[string]interface{}{
+			"error":       inProgress.Error(),
+			"product_ids": inProgress.ProductIDs,
+		})
+		return
+	}
+	if errors.Is(err, usecase.ErrAccountRestricted) {
+		c.respondError(w, http.StatusForbidden, err)
+		return
+	}
+	if err != nil {
+		c.respondError(w, http.StatusInternalServerError, err)
+		return
+	}
+
+	// 同じ Firebase アカウントで再登録されないよう、認証側のアカウントも消す
+	// (DBの匿名化は済んでいるので、失敗してもログに残すだけ)
+	if err := c.AuthClient.DeleteUser(r.Context(), firebaseUID); err != nil {
+		log.Printf("account: failed to delete firebase user: %v", err)
+	}
+	c.respondJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
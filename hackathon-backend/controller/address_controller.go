@@ -0,0 +1,138 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"hackathon-backend/model"
+	"hackathon-backend/usecase"
+	"net/http"
+
+	"firebase.google.com/go/auth"
+)
+
+type AddressController struct {
+	BaseController
+	Usecase *usecase.AddressUsecase
+}
+
+func NewAddressController(u *usecase.AddressUsecase, auth *auth.Client) *AddressController {
+	return &AddressController{
+		BaseController: BaseController{AuthClient: auth},
+		Usecase:        u,
+	}
+}
+
+// HandleList: GET /users/me/addresses
+func (c *AddressController) HandleList(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, err := c.verifyToken(r)
+	if err != nil {
+		c.respondError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	addresses, err := c.Usecase.List(firebaseUID)
+	if err != nil {
+		c.respondError(w, http.StatusInternalServerError, err)
+		return
+	}
+	c.respondJSON(w, http.StatusOK, addresses)
+}
+
+// HandleCreate: POST /users/me/addresses
+func (c *AddressController) HandleCreate(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, err := c.verifyToken(r)
+	if err != nil {
+		c.respondError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	req, ok := c.decodeAddressReq(w, r)
+	if !ok {
+		return
+	}
+
+	address, err := c.Usecase.Create(firebaseUID, req)
+	if err != nil {
+		c.respondAddressError(w, err)
+		return
+	}
+	c.respondJSON(w, http.StatusCreated, address)
+}
+
+// HandleUpdate: PUT /users/me/addresses/{id}
+func (c *AddressController) HandleUpdate(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, err := c.verifyToken(r)
+	if err != nil {
+		c.respondError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	req, ok := c.decodeAddressReq(w, r)
+	if !ok {
+		return
+	}
+
+	address, err := c.Usecase.Update(firebaseUID, r.PathValue("id"), req)
+	if err != nil {
+		c.respondAddressError(w, err)
+		return
+	}
+	c.respondJSON(w, http.StatusOK, address)
+}
+
+// HandleDelete: DELETE /users/me/addresses/{id}
+func (c *AddressController) HandleDelete(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, err := c.verifyToken(r)
+	if err != nil {
+		c.respondError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	if err := c.Usecase.Delete(firebaseUID, r.PathValue("id")); err != nil {
+		c.respondAddressError(w, err)
+		return
+	}
+	c.respondJSON(w, http.StatusOK, map[string]string{"status": "deleted"})
+}
+
+// HandleGetShipping: GET /products/{id}/shipping
+// 売れた商品の届け先を出品者にだけ返します (取引が終わったものは redacted: true で住所は空)
+func (c *AddressController) HandleGetShipping(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, err := c.verifyToken(r)
+	if err != nil {
+		c.respondError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	shipping, err := c.Usecase.GetSaleShipping(firebaseUID, r.PathValue("id"))
+	if err != nil {
+		c.respondAddressError(w, err)
+		return
+	}
+	c.respondJSON(w, http.StatusOK, shipping)
+}
+
+func (c *AddressController) decodeAddressReq(w http.ResponseWriter, r *http.Request) (*model.AddressReq, bool) {
+	var req model.AddressReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		c.respondError(w, http.StatusBadRequest, err)
+		return nil, false
+	}
+	if err := req.Validate(); err != nil {
+		c.respondError(w, http.StatusBadRequest, err)
+		return nil, false
+	}
+	return &req, true
+}
+
+// usecase のエラーをステータスコードに変換する
+func (c *AddressController) respondAddressError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrAddressNotFound), errors.Is(err, usecase.ErrShippingNotFound):
+		c.respondError(w, http.StatusNotFound, err)
+	case errors.Is(err, usecase.ErrTooManyAddresses):
+		c.respondError(w, http.StatusConflict, err)
+	default:
+		c.respondError(w, http.StatusInternalServerError, err)
+	}
+}
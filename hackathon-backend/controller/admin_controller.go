@@ -0,0 +1,211 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hackathon-backend/model"
+	"hackathon-backend/usecase"
+	"io"
+	"net/http"
+	"strconv"
+
+	"firebase.google.com/go/auth"
+)
+
+// AdminController: /admin 以下のハンドラ
+// すべて AuthMiddleware.RequireRole を通してから呼ばれる前提です
+type AdminController struct {
+	BaseController
+	Usecase *usecase.AdminUsecase
+}
+
+func NewAdminController(u *usecase.AdminUsecase, auth *auth.Client) *AdminController {
+	return &AdminController{
+		BaseController: BaseController{AuthClient: auth},
+		Usecase:        u,
+	}
+}
+
+// HandleSuspendUser: POST /admin/users/{id}/suspend
+func (c *AdminController) HandleSuspendUser(w http.ResponseWriter, r *http.Request) {
+	var req model.SuspendUserReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		c.respondError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.respondError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	user, err := c.Usecase.SuspendUser(currentUser(r), r.PathValue("id"), req.Reason, req.Until)
+	if err != nil {
+		c.respondAdminError(w, err)
+		return
+	}
+	c.respondJSON(w, http.StatusOK, user)
+}
+
+// HandleBanUser: POST /admin/users/{id}/ban
+func (c *AdminController) HandleBanUser(w http.ResponseWriter, r *http.Request) {
+	var req model.SuspendUserReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		c.respondError(w, http.StatusBadRequest, err)
+		return
+	}
+	// BANは無期限なので until は無視する
+	req.Until = nil
+	if err := req.Validate(); err != nil {
+		c.respondError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	user, err := c.Usecase.BanUser(currentUser(r), r.PathValue("id"), req.Reason)
+	if err != nil {
+		c.respondAdminError(w, err)
+		return
+	}
+	c.respondJSON(w, http.StatusOK, user)
+}
+
+// HandleUnsuspendUser: POST /admin/users/{id}/unsuspend
+func (c *AdminController) HandleUnsuspendUser(w http.ResponseWriter, r *http.Request) {
+	req, ok := c.decodeActionReq(w, r)
+	if !ok {
+		return
+	}
+
+	user, err := c.Usecase.UnsuspendUser(currentUser(r), r.PathValue("id"), req.Reason)
+	if err != nil {
+		c.respondAdminError(w, err)
+		return
+	}
+	c.respondJSON(w, http.StatusOK, user)
+}
+
+// HandleChangeRole: PUT /admin/users/{id}/role
+func (c *AdminController) HandleChangeRole(w http.ResponseWriter, r *http.Request) {
+	var req model.UpdateRoleReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		c.respondError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.respondError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	user, err := c.Usecase.ChangeRole(currentUser(r), r.PathValue("id"), req.Role)
+	if err != nil {
+		c.respondAdminError(w, err)
+		return
+	}
+	c.respondJSON(w, http.StatusOK, user)
+}
+
+// HandleTakeDownProduct: POST /admin/products/{id}/takedown
+func (c *AdminController) HandleTakeDownProduct(w http.ResponseWriter, r *http.Request) {
+	req, ok := c.decodeActionReq(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.Usecase.TakeDownProduct(currentUser(r), r.PathValue("id"), req.Reason); err != nil {
+		c.respondAdminError(w, err)
+		return
+	}
+	c.respondJSON(w, http.StatusOK, map[string]string{"status": "taken_down"})
+}
+
+// HandleRestoreProduct: POST /admin/products/{id}/restore
+func (c *AdminController) HandleRestoreProduct(w http.ResponseWriter, r *http.Request) {
+	req, ok := c.decodeActionReq(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.Usecase.RestoreProduct(currentUser(r), r.PathValue("id"), req.Reason); err != nil {
+		c.respondAdminError(w, err)
+		return
+	}
+	c.respondJSON(w, http.StatusOK, map[string]string{"status": "restored"})
+}
+
+// HandleCancelPurchase: POST /admin/products/{id}/cancel-purchase
+func (c *AdminController) HandleCancelPurchase(w http.ResponseWriter, r *http.Request) {
+	req, ok := c.decodeActionReq(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.Usecase.CancelPurchase(currentUser(r), r.PathValue("id"), req.Reason); err != nil {
+		c.respondAdminError(w, err)
+		return
+	}
+	c.respondJSON(w, http.StatusOK, map[string]string{"status": "cancelled"})
+}
+
+// HandleViewChat: GET /admin/messages?user_a=xxx&user_b=yyy&reason=...
+func (c *AdminController) HandleViewChat(w http.ResponseWriter, r *http.Request) {
+	userA := r.URL.Query().Get("user_a")
+	userB := r.URL.Query().Get("user_b")
+	reason := r.URL.Query().Get("reason")
+	if userA == "" || userB == "" {
+		c.respondError(w, http.StatusBadRequest, fmt.Errorf("user_a and user_b are required"))
+		return
+	}
+	// 閲覧理由は監査ログに残すので必須
+	if reason == "" {
+		c.respondError(w, http.StatusBadRequest, fmt.Errorf("reason is required"))
+		return
+	}
+
+	msgs, err := c.Usecase.ViewChat(currentUser(r), userA, userB, reason)
+	if err != nil {
+		c.respondAdminError(w, err)
+		return
+	}
+	c.respondJSON(w, http.StatusOK, msgs)
+}
+
+// HandleListAuditLogs: GET /admin/audit-logs?actor_id=&target_type=&target_id=&page=
+func (c *AdminController) HandleListAuditLogs(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	page, _ := strconv.Atoi(q.Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit := 50
+
+	logs, err := c.Usecase.ListAuditLogs(q.Get("actor_id"), q.Get("target_type"), q.Get("target_id"), page, limit)
+	if err != nil {
+		c.respondAdminError(w, err)
+		return
+	}
+	c.respondJSON(w, http.StatusOK, logs)
+}
+
+// 理由付きのリクエストボディを読む (ボディなしも許可)
+func (c *AdminController) decodeActionReq(w http.ResponseWriter, r *http.Request) (model.AdminActionReq, bool) {
+	var req model.AdminActionReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.respondError(w, http.StatusBadRequest, err)
+		return req, false
+	}
+	return req, true
+}
+
+// usecase のエラーをステータスコードに変換する
+func (c *AdminController) respondAdminError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrForbidden):
+		c.respondError(w, http.StatusForbidden, err)
+	case errors.Is(err, usecase.ErrAdminTargetNotFound):
+		c.respondError(w, http.StatusNotFound, err)
+	case errors.Is(err, usecase.ErrAdminNoChange):
+		c.respondError(w, http.StatusConflict, err)
+	default:
+		c.respondError(w, http.StatusInternalServerError, err)
+	}
+}
@@ -0,0 +1,180 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"hackathon-backend/service"
+	"hackathon-backend/usecase"
+
+	"firebase.google.com/go/auth"
+)
+
+// AIUsageController: AI系エンドポイントの利用制限と、利用額のレポート
+type AIUsageController struct {
+	BaseController
+	Usecase *usecase.AIUsageUsecase
+}
+
+func NewAIUsageController(u *usecase.AIUsageUsecase, auth *auth.Client) *AIUsageController {
+	return &AIUsageController{
+		BaseController: BaseController{AuthClient: auth},
+		Usecase:        u,
+	}
+}
+
+// Limit: 日次上限と同時実行数を確認してから next を実行し、使用量を記録する
+// AuthMiddleware.RequireActive (閲覧系なら RequireAuth) の内側で使ってください (currentUser が必要)
+func (c *AIUsageController) Limit(endpoint string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := currentUser(r)
+		if user == nil {
+			c.respondError(w, http.StatusForbidden, fmt.Errorf("user not registered"))
+			return
+		}
+
+		remaining, done, err := c.Usecase.Acquire(user.ID)
+		if err != nil {
+			c.respondLimitError(w, err)
+			return
+		}
+		// AIを呼ばなかったリクエストは上限に数えない
+		usedAI := false
+		defer func() { done(usedAI) }()
+
+		w.Header().Set("X-RateLimit-Limit", strconv.Itoa(c.Usecase.Config.DailyQuota))
+		w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(remaining))
+
+		ctx, rec := service.WithUsageRecorder(r.Context())
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+
+		next(sw, r.WithContext(ctx))
+
+		usage := rec.Usage()
+		usedAI = usage.Calls > 0
+		if err := c.Usecase.Record(user.ID, endpoint, usage, time.Since(start), sw.status >= 400); err != nil {
+			log.Printf("ai usage: failed to record usage for %s: %v", user.ID, err)
+		}
+	}
+}
+
+// Moderated: 自動審査でAIを呼ぶ書き込み系 (出品・更新・メッセージ送信) 用
+// 日次上限には数えず 1分あたりの回数だけ制限し、審査の使用量を endpoint として記録する
+// AuthMiddleware.RequireActive の内側で使ってください (currentUser が必要)
+func (c *AIUsageController) Moderated(endpoint string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := currentUser(r)
+		if user == nil {
+			c.respondError(w, http.StatusForbidden, fmt.Errorf("user not registered"))
+			return
+		}
+
+		if err := c.Usecase.AcquireModeration(user.ID); err != nil {
+			c.respondLimitError(w, err)
+			return
+		}
+
+		ctx, rec := service.WithUsageRecorder(r.Context())
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+
+		next(sw, r.WithContext(ctx))
+
+		// block の 422 などは審査自体はできているので、失敗にするのはサーバーエラーだけ
+		if err := c.Usecase.Record(user.ID, endpoint, rec.Usage(), time.Since(start), sw.status >= 500); err != nil {
+			log.Printf("ai usage: failed to record usage for %s: %v", user.ID, err)
+		}
+	}
+}
+
+// HandleSpendReport: GET /admin/ai-usage?from=2006-01-02&to=2006-01-02
+// 期間の指定がなければ直近30日。to の日も含みます
+func (c *AIUsageController) HandleSpendReport(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	y, m, d := time.Now().Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+
+	to := today.AddDate(0, 0, 1)
+	if s := q.Get("to"); s != "" {
+		t, err := time.ParseInLocation(time.DateOnly, s, time.Local)
+		if err != nil {
+			c.respondError(w, http.StatusBadRequest, fmt.Errorf("invalid to: %w", err))
+			return
+		}
+		to = t.AddDate(0, 0, 1)
+	}
+	from := to.AddDate(0, 0, -30)
+	if s := q.Get("from"); s != "" {
+		t, err := time.ParseInLocation(time.DateOnly, s, time.Local)
+		if err != nil {
+			c.respondError(w, http.StatusBadRequest, fmt.Errorf("invalid from: %w", err))
+			return
+		}
+		from = t
+	}
+	if !from.Before(to) {
+		c.respondError(w, http.StatusBadRequest, fmt.Errorf("from must be before to"))
+		return
+	}
+
+	report, err := c.Usecase.SpendReport(from, to)
+	if err != nil {
+		c.respondError(w, http.StatusInternalServerError, err)
+		return
+	}
+	c.respondJSON(w, http.StatusOK, report)
+}
+
+// 上限エラーを 429 + Retry-After で返す
+func (c *AIUsageController) respondLimitError(w http.ResponseWriter, err error) {
+	var quota *usecase.AIQuotaExceededError
+	var rate *usecase.AIRateLimitedError
+	switch {
+	case errors.As(err, &quota):
+		retryAfter := int(math.Ceil(quota.RetryAfter.Seconds()))
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+		c.respondJSON(w, http.StatusTooManyRequests, map[string]interface{}{
+			"error":       quota.Error(),
+			"retry_after": retryAfter,
+		})
+	case errors.As(err, &rate):
+		retryAfter := int(math.Ceil(rate.RetryAfter.Seconds()))
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+		c.respondJSON(w, http.StatusTooManyRequests, map[string]interface{}{
+			"error":       rate.Error(),
+			"retry_after": retryAfter,
+		})
+	case errors.Is(err, usecase.ErrAIBusy):
+		w.Header().Set("Retry-After", "5")
+		c.respondJSON(w, http.StatusTooManyRequests, map[string]interface{}{
+			"error":       err.Error(),
+			"retry_after": 5,
+		})
+	default:
+		c.respondError(w, http.StatusInternalServerError, err)
+	}
+}
+
+// statusWriter: ハンドラが返したステータスコードを覚えておく
+// SSE のハンドラでも使えるよう Flush はそのまま通します
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
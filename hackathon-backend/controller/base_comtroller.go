@@ -18,6 +18,11 @@ type BaseController struct {
 
 // verifyToken: AuthorizationヘッダーからUIDを取得する共通関数
 func (b *BaseController) verifyToken(r *http.Request) (string, error) {
+	// AuthMiddleware で検証済みならそれを使う
+	if uid, ok := r.Context().Value(firebaseUIDKey).(string); ok && uid != "" {
+		return uid, nil
+	}
+
 	authHeader := r.Header.Get("Authorization")
 	idToken := strings.Replace(authHeader, "Bearer ", "", 1)
 	if idToken == "" {
@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"errors"
+	"hackathon-backend/usecase"
+	"net/http"
+	"strconv"
+
+	"firebase.google.com/go/auth"
+)
+
+type FeedController struct {
+	BaseController
+	Usecase *usecase.FeedUsecase
+}
+
+func NewFeedController(u *usecase.FeedUsecase, auth *auth.Client) *FeedController {
+	return &FeedController{
+		BaseController: BaseController{AuthClient: auth},
+		Usecase:        u,
+	}
+}
+
+// HandleGetFeed: GET /products/feed?feed_id=...&page=2
+// 1ページ目は feed_id なしで呼び、返ってきた feed_id を2ページ目以降に付けてください
+// 未ログインの場合は急上昇順になります
+// feed_id の並びが期限切れなら 410 を返すので、feed_id なしで1ページ目から取り直してください
+func (c *FeedController) HandleGetFeed(w http.ResponseWriter, r *http.Request) {
+	viewerID := ""
+	if uid, err := c.verifyToken(r); err == nil {
+		viewerID = uid
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	res, err := c.Usecase.GetFeed(r.Context(), viewerID, r.URL.Query().Get("feed_id"), page, limit)
+	if errors.Is(err, usecase.ErrFeedExpired) {
+		c.respondError(w, http.StatusGone, err)
+		return
+	}
+	if err != nil {
+		c.respondError(w, http.StatusInternalServerError, err)
+		return
+	}
+	c.respondJSON(w, http.StatusOK, res)
+}
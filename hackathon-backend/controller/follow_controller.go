@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"errors"
+	"hackathon-backend/model"
+	"hackathon-backend/usecase"
+	"net/http"
+	"strconv"
+
+	"firebase.google.com/go/auth"
+)
+
+type FollowController struct {
+	BaseController
+	Usecase *usecase.FollowUsecase
+}
+
+func NewFollowController(u *usecase.FollowUsecase, auth *auth.Client) *FollowController {
+	return &FollowController{
+		BaseController: BaseController{AuthClient: auth},
+		Usecase:        u,
+	}
+}
+
+// HandleFollow: POST /users/{id}/follow ({id} はIDまたはハンドル)
+func (c *FollowController) HandleFollow(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, err := c.verifyToken(r)
+	if err != nil {
+		c.respondError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	if err := c.Usecase.Follow(firebaseUID, r.PathValue("id")); err != nil {
+		c.respondFollowError(w, err)
+		return
+	}
+	c.respondJSON(w, http.StatusOK, map[string]bool{"following": true})
+}
+
+// HandleUnfollow: DELETE /users/{id}/follow
+func (c *FollowController) HandleUnfollow(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, err := c.verifyToken(r)
+	if err != nil {
+		c.respondError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	if err := c.Usecase.Unfollow(firebaseUID, r.PathValue("id")); err != nil {
+		c.respondFollowError(w, err)
+		return
+	}
+	c.respondJSON(w, http.StatusOK, map[string]bool{"following": false})
+}
+
+// HandleGetFollowers: GET /users/{id}/followers?page=1 ({id} はIDまたはハンドル。me なら自分)
+func (c *FollowController) HandleGetFollowers(w http.ResponseWriter, r *http.Request) {
+	c.handleList(w, r, c.Usecase.ListFollowers)
+}
+
+// HandleGetFollowing: GET /users/{id}/following?page=1 ({id} はIDまたはハンドル。me なら自分)
+func (c *FollowController) HandleGetFollowing(w http.ResponseWriter, r *http.Request) {
+	c.handleList(w, r, c.Usecase.ListFollowing)
+}
+
+func (c *FollowController) handleList(w http.ResponseWriter, r *http.Request, list func(targetUserID, viewerFirebaseUID string, page, limit int) (*model.FollowUserPage, error)) {
+	userID := r.PathValue("id")
+	viewerID := ""
+	if uid, err := c.verifyToken(r); err == nil {
+		viewerID = uid
+	} else if userID == "me" {
+		c.respondError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	res, err := list(userID, viewerID, page, limit)
+	if err != nil {
+		c.respondFollowError(w, err)
+		return
+	}
+	c.respondJSON(w, http.StatusOK, res)
+}
+
+// HandleGetFollowingProducts: GET /users/me/following/products?page=1
+// フォロー中の出品者の出品中の商品を新着順で返します
+func (c *FollowController) HandleGetFollowingProducts(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, err := c.verifyToken(r)
+	if err != nil {
+		c.respondError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	res, err := c.Usecase.GetFollowingProducts(firebaseUID, page, limit)
+	if err != nil {
+		c.respondError(w, http.StatusInternalServerError, err)
+		return
+	}
+	c.respondJSON(w, http.StatusOK, res)
+}
+
+// usecase のエラーをステータスコードに変換する
+func (c *FollowController) respondFollowError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrCannotFollowSelf):
+		c.respondError(w, http.StatusBadRequest, err)
+	case errors.Is(err, usecase.ErrFollowTargetNotFound):
+		c.respondError(w, http.StatusNotFound, err)
+	default:
+		c.respondError(w, http.StatusInternalServerError, err)
+	}
+}
@@ -0,0 +1,93 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"hackathon-backend/usecase"
+	"io"
+	"net/http"
+	"strconv"
+
+	"firebase.google.com/go/auth"
+)
+
+type ListingReviewController struct {
+	BaseController
+	Usecase *usecase.ListingReviewUsecase
+}
+
+func NewListingReviewController(u *usecase.ListingReviewUsecase, auth *auth.Client) *ListingReviewController {
+	return &ListingReviewController{
+		BaseController: BaseController{AuthClient: auth},
+		Usecase:        u,
+	}
+}
+
+// HandleReviewProduct: POST /products/{id}/review-listing
+// 出品済みの商品を採点して改善提案を返します (出品者本人のみ)
+func (c *ListingReviewController) HandleReviewProduct(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, err := c.verifyToken(r)
+	if err != nil {
+		c.respondError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	review, err := c.Usecase.ReviewProduct(r.Context(), firebaseUID, r.PathValue("id"))
+	if err != nil {
+		switch {
+		case errors.Is(err, usecase.ErrListingNotFound):
+			c.respondError(w, http.StatusNotFound, err)
+		case errors.Is(err, usecase.ErrNotListingOwner):
+			c.respondError(w, http.StatusForbidden, err)
+		default:
+			c.respondError(w, http.StatusInternalServerError, err)
+		}
+		return
+	}
+	c.respondJSON(w, http.StatusOK, review)
+}
+
+// HandleReviewDraft: POST /products/review-listing
+// 出品前の内容を採点します。フォームは出品登録と同じ (name, description, price, image)。画像は任意です
+func (c *ListingReviewController) HandleReviewDraft(w http.ResponseWriter, r *http.Request) {
+	if _, err := c.verifyToken(r); err != nil {
+		c.respondError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	if err := r.ParseMultipartForm(10 << 20); err != nil {
+		c.respondError(w, http.StatusBadRequest, err)
+		return
+	}
+	name := r.FormValue("name")
+	description := r.FormValue("description")
+	if name == "" && description == "" {
+		c.respondError(w, http.StatusBadRequest, fmt.Errorf("name or description is required"))
+		return
+	}
+	price := 0
+	if s := r.FormValue("price"); s != "" {
+		p, err := strconv.Atoi(s)
+		if err != nil {
+			c.respondError(w, http.StatusBadRequest, err)
+			return
+		}
+		price = p
+	}
+
+	var imgData []byte
+	file, _, err := r.FormFile("image")
+	if err == nil {
+		defer file.Close()
+		imgData, err = io.ReadAll(file)
+		if err != nil {
+			c.respondError(w, http.StatusInternalServerError, err)
+			return
+		}
+	} else if !errors.Is(err, http.ErrMissingFile) {
+		c.respondError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	c.respondJSON(w, http.StatusOK, c.Usecase.ReviewDraft(r.Context(), name, description, price, imgData))
+}
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"encoding/json"
+	"fmt"
 	"hackathon-backend/model"
 	"hackathon-backend/usecase"
 	"net/http"
@@ -40,8 +41,11 @@ func (c *MessageController) HandleSendMessage(w http.ResponseWriter, r *http.Req
 		return
 	}
 
-	msg, err := c.Usecase.SendMessage(firebaseUID, req.ReceiverID, req.Content, req.ProductID)
+	msg, err := c.Usecase.SendMessage(r.Context(), firebaseUID, req.ReceiverID, req.Content, req.ProductID)
 	if err != nil {
+		if c.respondIfBlocked(w, err) {
+			return
+		}
 		c.respondError(w, http.StatusInternalServerError, err)
 		return
 	}
@@ -49,7 +53,9 @@ func (c *MessageController) HandleSendMessage(w http.ResponseWriter, r *http.Req
 	c.respondJSON(w, http.StatusOK, msg)
 }
 
-// HandleGetChat: GET /messages?user_id=相手のID
+// HandleGetChat: GET /messages?user_id=相手のID[&translate=true&lang=en]
+// lang を省略した場合はプロフィールの表示言語に翻訳します
+// translate=true のときはルーターで AI の利用上限を確認します (未翻訳の分だけLLMを呼び、使用量として記録)
 func (c *MessageController) HandleGetChat(w http.ResponseWriter, r *http.Request) {
 	firebaseUID, err := c.verifyToken(r)
 	if err != nil {
@@ -63,7 +69,14 @@ func (c *MessageController) HandleGetChat(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	msgs, err := c.Usecase.GetChatHistory(firebaseUID, otherUserID)
+	translate := r.URL.Query().Get("translate") == "true"
+	lang := r.URL.Query().Get("lang")
+	if lang != "" && !model.IsValidLanguage(lang) {
+		c.respondError(w, http.StatusBadRequest, fmt.Errorf("unsupported lang: %q", lang))
+		return
+	}
+
+	msgs, err := c.Usecase.GetChatHistory(r.Context(), firebaseUID, otherUserID, translate, lang)
 	if err != nil {
 		c.respondError(w, http.StatusInternalServerError, err)
 		return
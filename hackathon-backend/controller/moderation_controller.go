@@ -0,0 +1,54 @@
+package controller
+
+import (
+	"errors"
+	"hackathon-backend/usecase"
+	"net/http"
+	"strconv"
+
+	"firebase.google.com/go/auth"
+)
+
+type ModerationController struct {
+	BaseController
+	Usecase *usecase.ModerationUsecase
+}
+
+func NewModerationController(u *usecase.ModerationUsecase, auth *auth.Client) *ModerationController {
+	return &ModerationController{
+		BaseController: BaseController{AuthClient: auth},
+		Usecase:        u,
+	}
+}
+
+// HandleListResults: GET /admin/moderation?decision=flag&target_type=product&target_id=&page=
+// AuthMiddleware.RequireRole(moderator) を通してから呼ばれる前提です
+func (c *ModerationController) HandleListResults(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	page, _ := strconv.Atoi(q.Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit := 50
+
+	results, err := c.Usecase.ListResults(q.Get("decision"), q.Get("target_type"), q.Get("target_id"), page, limit)
+	if err != nil {
+		c.respondError(w, http.StatusInternalServerError, err)
+		return
+	}
+	c.respondJSON(w, http.StatusOK, results)
+}
+
+// respondIfBlocked: 自動審査で拒否されたエラーなら 422 で理由を返して true を返す
+func (b *BaseController) respondIfBlocked(w http.ResponseWriter, err error) bool {
+	var blocked *usecase.ContentBlockedError
+	if !errors.As(err, &blocked) {
+		return false
+	}
+	b.respondJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+		"error":      "content blocked by moderation",
+		"categories": blocked.Result.Categories,
+		"reasons":    blocked.Result.Reasons,
+	})
+	return true
+}
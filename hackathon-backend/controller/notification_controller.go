@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"hackathon-backend/usecase"
+	"net/http"
+	"strconv"
+
+	"firebase.google.com/go/auth"
+)
+
+type NotificationController struct {
+	BaseController
+	Usecase *usecase.NotificationUsecase
+}
+
+func NewNotificationController(u *usecase.NotificationUsecase, auth *auth.Client) *NotificationController {
+	return &NotificationController{
+		BaseController: BaseController{AuthClient: auth},
+		Usecase:        u,
+	}
+}
+
+// HandleGetNotifications: GET /users/me/notifications?page=1 (新しい順、未読数付き)
+func (c *NotificationController) HandleGetNotifications(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, err := c.verifyToken(r)
+	if err != nil {
+		c.respondError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	res, err := c.Usecase.List(firebaseUID, page, limit)
+	if err != nil {
+		c.respondError(w, http.StatusInternalServerError, err)
+		return
+	}
+	c.respondJSON(w, http.StatusOK, res)
+}
+
+// HandleMarkAllRead: POST /users/me/notifications/read
+func (c *NotificationController) HandleMarkAllRead(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, err := c.verifyToken(r)
+	if err != nil {
+		c.respondError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	if err := c.Usecase.MarkAllRead(firebaseUID); err != nil {
+		c.respondError(w, http.StatusInternalServerError, err)
+		return
+	}
+	c.respondJSON(w, http.StatusOK, map[string]string{"status": "read"})
+}
@@ -3,10 +3,12 @@ package controller
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"hackathon-backend/model"
 	"hackathon-backend/usecase"
 	"io"
+	"log"
 	"net/http"
 
 	"firebase.google.com/go/auth"
@@ -24,7 +26,7 @@ func NewProductDescriptionController(u *usecase.ProductDescriptionUsecase, auth
 	}
 }
 
-// HandleGenerate: POST /products/generate-description
+// HandleGenerate: POST /products/generate-description (?regenerate=true でキャッシュを使わずに生成)
 func (c *ProductDescriptionController) HandleGenerate(w http.ResponseWriter, r *http.Request) {
 	// ログインチェック
 	_, err := c.verifyToken(r)
@@ -39,19 +41,114 @@ func (c *ProductDescriptionController) HandleGenerate(w http.ResponseWriter, r *
 		return
 	}
 
-	if req.Name == "" {
-		c.respondError(w, http.StatusBadRequest, nil)
+	if err := req.Validate(); err != nil {
+		c.respondError(w, http.StatusBadRequest, err)
 		return
 	}
 
 	// 生成実行
-	desc, err := c.Usecase.Generate(r.Context(), req.Name, req.Keywords)
+	res, err := c.Usecase.Generate(r.Context(), &req, wantsRegenerate(r))
 	if err != nil {
-		c.respondError(w, http.StatusInternalServerError, err)
+		c.respondGenerateError(w, err)
 		return
 	}
 
-	c.respondJSON(w, http.StatusOK, model.GenerateRes{Description: desc})
+	c.respondJSON(w, http.StatusOK, res)
+}
+
+// HandleGenerateStream: POST /products/generate-description/stream
+// 生成された文章を Server-Sent Events で逐次返します (fetch のストリーム読み取りで受信する想定)
+//
+//	event: chunk  data: {"text": "..."}   … 生成された文章の断片
+//	event: done   data: {}               … 生成完了
+//	event: error  data: {"error": "..."} … 途中で失敗した場合
+//
+// 最初の断片が届く前に失敗した場合は通常のJSONエラーを返します。複数案 (drafts > 1) には対応しません
+func (c *ProductDescriptionController) HandleGenerateStream(w http.ResponseWriter, r *http.Request) {
+	if _, err := c.verifyToken(r); err != nil {
+		c.respondError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	var req model.GenerateReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		c.respondError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.respondError(w, http.StatusBadRequest, err)
+		return
+	}
+	if req.Drafts > 1 {
+		c.respondError(w, http.StatusBadRequest, fmt.Errorf("streaming supports only a single draft"))
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		c.respondError(w, http.StatusInternalServerError, fmt.Errorf("streaming is not supported"))
+		return
+	}
+
+	// ヘッダーは最初の断片が届いたときに送る
+	started := false
+	start := func() {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.Header().Set("X-Accel-Buffering", "no") // プロキシでバッファリングさせない
+		w.WriteHeader(http.StatusOK)
+		started = true
+	}
+
+	// r.Context() はクライアントの切断でキャンセルされ、LLMの生成も止まる
+	err := c.Usecase.GenerateStream(r.Context(), &req, wantsRegenerate(r), func(chunk string) error {
+		if !started {
+			start()
+		}
+		if err := writeSSE(w, "chunk", map[string]string{"text": chunk}); err != nil {
+			return err
+		}
+		flusher.Flush()
+		return nil
+	})
+	if err != nil {
+		if r.Context().Err() != nil {
+			// クライアントが切断済みなので何も返さない
+			log.Printf("generate stream: client disconnected: %v", err)
+			return
+		}
+		if !started {
+			c.respondGenerateError(w, err)
+			return
+		}
+		log.Printf("generate stream: %v", err)
+		writeSSE(w, "error", map[string]string{"error": err.Error()})
+		flusher.Flush()
+		return
+	}
+
+	if !started {
+		start()
+	}
+
+	writeSSE(w, "done", struct{}{})
+	flusher.Flush()
+}
+
+// wantsRegenerate: ?regenerate=true ならキャッシュを使わずに生成し直す
+func wantsRegenerate(r *http.Request) bool {
+	return r.URL.Query().Get("regenerate") == "true"
+}
+
+// writeSSE: Server-Sent Events の1イベントを書き込む
+func writeSSE(w io.Writer, event string, data interface{}) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, b)
+	return err
 }
 
 // ★追加: 画像アップロード解析ハンドラ
@@ -84,12 +181,29 @@ func (c *ProductDescriptionController) HandleGenerateFromImage(w http.ResponseWr
 	}
 
 	// 4. Usecase実行
-	res, err := c.Usecase.GenerateInfoFromImage(r.Context(), buf.Bytes(), mimeType)
+	res, err := c.Usecase.GenerateInfoFromImage(r.Context(), buf.Bytes(), mimeType, wantsRegenerate(r))
 	if err != nil {
-		c.respondError(w, http.StatusInternalServerError, err)
+		c.respondGenerateError(w, err)
 		return
 	}
 
 	// 5. 結果を返す
 	c.respondJSON(w, http.StatusOK, res)
 }
+
+// usecase のエラーをステータスコードに変換する
+func (c *ProductDescriptionController) respondGenerateError(w http.ResponseWriter, err error) {
+	// AIの出力が不正だった場合は項目ごとのエラーを返す
+	var invalid *usecase.AIOutputInvalidError
+	switch {
+	case errors.As(err, &invalid):
+		c.respondJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+			"error":  "AI could not produce valid output",
+			"fields": invalid.Fields,
+		})
+	case errors.Is(err, usecase.ErrUnknownPromptVersion):
+		c.respondError(w, http.StatusBadRequest, err)
+	default:
+		c.respondError(w, http.StatusInternalServerError, err)
+	}
+}
@@ -1,8 +1,12 @@
 package controller
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"hackathon-backend/usecase"
+	"net"
 	"net/http"
+	"strings"
 
 	"firebase.google.com/go/auth"
 )
@@ -10,10 +14,12 @@ import (
 type ProductDetailController struct {
 	BaseController
 	Usecase *usecase.ProductDetailUsecase
+	// 手前にある信頼できるプロキシの数 (0 なら X-Forwarded-For を見ない)
+	TrustedProxyHops int
 }
 
-func NewProductDetailController(u *usecase.ProductDetailUsecase, auth *auth.Client) *ProductDetailController {
-	return &ProductDetailController{BaseController: BaseController{AuthClient: auth}, Usecase: u}
+func NewProductDetailController(u *usecase.ProductDetailUsecase, auth *auth.Client, trustedProxyHops int) *ProductDetailController {
+	return &ProductDetailController{BaseController: BaseController{AuthClient: auth}, Usecase: u, TrustedProxyHops: trustedProxyHops}
 }
 
 func (c *ProductDetailController) HandleGetProduct(w http.ResponseWriter, r *http.Request) {
@@ -26,10 +32,36 @@ func (c *ProductDetailController) HandleGetProduct(w http.ResponseWriter, r *htt
 	productID := r.PathValue("id")
 
 	// ★引数に viewerID を追加
-	product, err := c.Usecase.GetProductByID(productID, viewerID)
+	product, err := c.Usecase.GetProductByID(productID, viewerID, anonymousViewerKey(r, c.TrustedProxyHops))
 	if err != nil {
 		c.respondError(w, http.StatusNotFound, err)
 		return
 	}
 	c.respondJSON(w, http.StatusOK, product)
 }
+
+// anonymousViewerKey: 未ログインの閲覧者を区別するキー (IPアドレスとUser-Agentのハッシュ)
+// IPアドレスそのものは保存しない
+func anonymousViewerKey(r *http.Request, trustedProxyHops int) string {
+	sum := sha256.Sum256([]byte(clientIP(r, trustedProxyHops) + "|" + r.UserAgent()))
+	return "anon:" + hex.EncodeToString(sum[:16])
+}
+
+// clientIP: リクエスト元のIPアドレス
+// X-Forwarded-For は各プロキシが右端に追記するので、信頼できるプロキシの数だけ右から数えた値を使います
+// (左側はクライアントが自由に書けるので信用しない)。プロキシがない設定では RemoteAddr を使います
+func clientIP(r *http.Request, trustedProxyHops int) string {
+	if trustedProxyHops > 0 {
+		hops := strings.Split(r.Header.Get("X-Forwarded-For"), ",")
+		if i := len(hops) - trustedProxyHops; i >= 0 {
+			if ip := strings.TrimSpace(hops[i]); ip != "" {
+				return ip
+			}
+		}
+	}
+	ip := r.RemoteAddr
+	if host, _, err := net.SplitHostPort(ip); err == nil {
+		ip = host
+	}
+	return ip
+}
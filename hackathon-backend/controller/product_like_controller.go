@@ -1,6 +1,8 @@
 package controller
 
 import (
+	"database/sql"
+	"errors"
 	"hackathon-backend/usecase"
 	"net/http"
 
@@ -19,6 +21,12 @@ func NewProductLikeController(u *usecase.ProductLikeUsecase, auth *auth.Client)
 	}
 }
 
+// いいね操作のレスポンス
+type likeRes struct {
+	Liked     bool `json:"liked"`
+	LikeCount int  `json:"like_count"`
+}
+
 // HandleToggleLike: POST /products/{id}/like
 func (c *ProductLikeController) HandleToggleLike(w http.ResponseWriter, r *http.Request) {
 	firebaseUID, err := c.verifyToken(r)
@@ -29,14 +37,46 @@ func (c *ProductLikeController) HandleToggleLike(w http.ResponseWriter, r *http.
 
 	productID := r.PathValue("id")
 	// 切り替え実行
-	liked, err := c.Usecase.ToggleLike(productID, firebaseUID)
+	liked, count, err := c.Usecase.ToggleLike(productID, firebaseUID)
 	if err != nil {
-		c.respondError(w, http.StatusInternalServerError, err)
+		c.respondLikeError(w, err)
 		return
 	}
 
 	// 結果(true/false)を返す
-	c.respondJSON(w, http.StatusOK, map[string]bool{"liked": liked})
+	c.respondJSON(w, http.StatusOK, likeRes{Liked: liked, LikeCount: count})
+}
+
+// HandleLike: PUT /products/{id}/like (何度呼んでもいいね済みになるだけ)
+func (c *ProductLikeController) HandleLike(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, err := c.verifyToken(r)
+	if err != nil {
+		c.respondError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	count, err := c.Usecase.Like(r.PathValue("id"), firebaseUID)
+	if err != nil {
+		c.respondLikeError(w, err)
+		return
+	}
+	c.respondJSON(w, http.StatusOK, likeRes{Liked: true, LikeCount: count})
+}
+
+// HandleUnlike: DELETE /products/{id}/like (何度呼んでも解除済みになるだけ)
+func (c *ProductLikeController) HandleUnlike(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, err := c.verifyToken(r)
+	if err != nil {
+		c.respondError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	count, err := c.Usecase.Unlike(r.PathValue("id"), firebaseUID)
+	if err != nil {
+		c.respondLikeError(w, err)
+		return
+	}
+	c.respondJSON(w, http.StatusOK, likeRes{Liked: false, LikeCount: count})
 }
 
 // HandleGetLikeStatus: GET /products/{id}/like
@@ -57,3 +97,11 @@ func (c *ProductLikeController) HandleGetLikeStatus(w http.ResponseWriter, r *ht
 
 	c.respondJSON(w, http.StatusOK, map[string]bool{"liked": liked})
 }
+
+func (c *ProductLikeController) respondLikeError(w http.ResponseWriter, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		c.respondError(w, http.StatusNotFound, errors.New("product not found"))
+		return
+	}
+	c.respondError(w, http.StatusInternalServerError, err)
+}
@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"hackathon-backend/model"
+	"hackathon-backend/usecase"
+	"net/http"
+
+	"firebase.google.com/go/auth"
+)
+
+type ProductPriceController struct {
+	BaseController
+	Usecase *usecase.ProductPriceUsecase
+}
+
+func NewProductPriceController(u *usecase.ProductPriceUsecase, auth *auth.Client) *ProductPriceController {
+	return &ProductPriceController{
+		BaseController: BaseController{AuthClient: auth},
+		Usecase:        u,
+	}
+}
+
+// HandleSuggestPrice: POST /products/suggest-price
+// 類似の取引実績が足りない場合は price_suggestion を null で返します
+func (c *ProductPriceController) HandleSuggestPrice(w http.ResponseWriter, r *http.Request) {
+	if _, err := c.verifyToken(r); err != nil {
+		c.respondError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	var req model.SuggestPriceReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		c.respondError(w, http.StatusBadRequest, err)
+		return
+	}
+	if req.Name == "" && req.Keywords == "" {
+		c.respondError(w, http.StatusBadRequest, fmt.Errorf("name or keywords is required"))
+		return
+	}
+
+	suggestion, err := c.Usecase.SuggestPrice(r.Context(), req.Name, req.Keywords, req.Explain)
+	if err != nil {
+		c.respondError(w, http.StatusInternalServerError, err)
+		return
+	}
+	c.respondJSON(w, http.StatusOK, map[string]interface{}{"price_suggestion": suggestion})
+}
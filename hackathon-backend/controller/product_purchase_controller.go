@@ -1,6 +1,9 @@
 package controller
 
 import (
+	"encoding/json"
+	"errors"
+	"hackathon-backend/model"
 	"hackathon-backend/usecase"
 	"net/http"
 
@@ -16,6 +19,8 @@ func NewProductPurchaseController(u *usecase.ProductPurchaseUsecase, auth *auth.
 	return &ProductPurchaseController{BaseController: BaseController{AuthClient: auth}, Usecase: u}
 }
 
+// HandlePurchaseProduct: POST /products/{id}/purchase  {"address_id": "..."}
+// 届け先は住所録 (/users/me/addresses) から選びます
 func (c *ProductPurchaseController) HandlePurchaseProduct(w http.ResponseWriter, r *http.Request) {
 	firebaseUID, err := c.verifyToken(r)
 	if err != nil {
@@ -24,7 +29,17 @@ func (c *ProductPurchaseController) HandlePurchaseProduct(w http.ResponseWriter,
 	}
 	productID := r.PathValue("id")
 
-	err = c.Usecase.PurchaseProduct(productID, firebaseUID)
+	var req model.PurchaseReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		c.respondError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	err = c.Usecase.PurchaseProduct(productID, firebaseUID, req.AddressID)
+	if errors.Is(err, usecase.ErrAddressNotFound) {
+		c.respondError(w, http.StatusNotFound, err)
+		return
+	}
 	if err != nil {
 		c.respondError(w, http.StatusBadRequest, err)
 		return
@@ -56,6 +56,7 @@ func (c *ProductRegisterController) Handler(w http.ResponseWriter, r *http.Reque
 	defer file.Close()
 	//  Usecase 実行
 	product, err := c.Usecase.RegisterProduct(
+		r.Context(),
 		firebaseUID,
 		name,
 		description,
@@ -65,6 +66,9 @@ func (c *ProductRegisterController) Handler(w http.ResponseWriter, r *http.Reque
 	)
 
 	if err != nil {
+		if c.respondIfBlocked(w, err) {
+			return
+		}
 		c.respondError(w, http.StatusInternalServerError, err)
 		return
 	}
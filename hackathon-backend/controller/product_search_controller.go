@@ -1,9 +1,12 @@
 package controller
 
 import (
+	"fmt"
+	"hackathon-backend/model"
 	"hackathon-backend/usecase"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"firebase.google.com/go/auth"
 )
@@ -21,6 +24,13 @@ func NewProductSearchController(u *usecase.ProductSearchUsecase, auth *auth.Clie
 }
 
 // HandleListProducts が GET /products の処理です
+//
+//	?q=...                 … 商品名の部分一致
+//	?q=...&mode=natural    … 「白いスニーカー 5000円以下 サイズ27」のような文章を条件に分解して検索し、
+//	                         読み取った条件を interpreted で返す
+//	?keywords=a,b&min_price=&max_price=&color=&size=  … interpreted をUIで編集したあとの条件
+//	?sort=&status=         … 並び順 (newest / oldest / price_asc / price_desc / likes / trending)・状態
+//	                         (mode=natural でも指定すればこちらを優先)
 func (c *ProductSearchController) HandleListProducts(w http.ResponseWriter, r *http.Request) {
 	// ★追加: ログインしていれば閲覧者IDを取得（未ログインなら空文字）
 	viewerID := ""
@@ -29,8 +39,17 @@ func (c *ProductSearchController) HandleListProducts(w http.ResponseWriter, r *h
 	}
 
 	keyword := r.URL.Query().Get("q")
-	sortOrder := r.URL.Query().Get("sort")
-	status := r.URL.Query().Get("status")
+	natural := r.URL.Query().Get("mode") == "natural"
+
+	query := &model.SearchQuery{Keywords: []string{}, Attributes: []model.SearchAttribute{}}
+	if natural {
+		query = usecase.ParseSearchQuery(keyword)
+		keyword = ""
+	}
+	if err := applySearchParams(r, query); err != nil {
+		c.respondError(w, http.StatusBadRequest, err)
+		return
+	}
 
 	pageStr := r.URL.Query().Get("page")
 	page, _ := strconv.Atoi(pageStr)
@@ -40,15 +59,98 @@ func (c *ProductSearchController) HandleListProducts(w http.ResponseWriter, r *h
 	limit := 20 // 1ページの件数
 
 	// ★引数に viewerID を追加して呼び出し
-	products, err := c.Usecase.SearchProduct(keyword, sortOrder, status, viewerID, page, limit)
+	products, err := c.Usecase.SearchProduct(keyword, query, viewerID, page, limit)
 	if err != nil {
 		c.respondError(w, http.StatusInternalServerError, err)
 		return
 	}
+	if natural {
+		products.Interpreted = query
+	}
+
+	c.respondJSON(w, http.StatusOK, products)
+}
+
+// HandleGetTrending: GET /products/trending?page=1
+func (c *ProductSearchController) HandleGetTrending(w http.ResponseWriter, r *http.Request) {
+	viewerID := ""
+	if uid, err := c.verifyToken(r); err == nil {
+		viewerID = uid
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
 
+	products, err := c.Usecase.GetTrending(viewerID, page, 20)
+	if err != nil {
+		c.respondError(w, http.StatusInternalServerError, err)
+		return
+	}
 	c.respondJSON(w, http.StatusOK, products)
 }
 
+// applySearchParams: クエリパラメータで指定された条件で上書きする
+func applySearchParams(r *http.Request, q *model.SearchQuery) error {
+	params := r.URL.Query()
+
+	if v := params.Get("sort"); v != "" {
+		q.Sort = v
+	}
+	if v := params.Get("status"); v != "" {
+		q.Status = v
+	}
+	if params.Has("keywords") {
+		q.Keywords = []string{}
+		for _, k := range strings.Split(params.Get("keywords"), ",") {
+			if k = strings.TrimSpace(k); k != "" {
+				q.Keywords = append(q.Keywords, k)
+			}
+		}
+	}
+	for _, name := range []string{"min_price", "max_price"} {
+		if !params.Has(name) {
+			continue
+		}
+		price := 0
+		if v := params.Get(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 0 {
+				return fmt.Errorf("invalid %s: %q", name, v)
+			}
+			price = n
+		}
+		if name == "min_price" {
+			q.MinPrice = price
+		} else {
+			q.MaxPrice = price
+		}
+	}
+	if q.MinPrice > 0 && q.MaxPrice > 0 && q.MinPrice > q.MaxPrice {
+		return fmt.Errorf("min_price must not exceed max_price")
+	}
+	for _, name := range []string{model.SearchAttrColor, model.SearchAttrSize} {
+		if !params.Has(name) {
+			continue
+		}
+		// 同じ属性は置き換える (空なら外す)
+		attrs := []model.SearchAttribute{}
+		for _, a := range q.Attributes {
+			if a.Name != name {
+				attrs = append(attrs, a)
+			}
+		}
+		for _, v := range strings.Split(params.Get(name), ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				attrs = append(attrs, model.SearchAttribute{Name: name, Value: v})
+			}
+		}
+		q.Attributes = attrs
+	}
+	return nil
+}
+
 // GET /users/{id}/products (公開ユーザーページ用)
 func (c *ProductSearchController) HandleGetByUserID(w http.ResponseWriter, r *http.Request) {
 	// ★追加: 閲覧者IDを取得
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -48,6 +49,7 @@ func (c *ProductUpdateController) HandleUpdateProduct(w http.ResponseWriter, r *
 
 	// 4. 更新実行
 	updatedProduct, err := c.Usecase.UpdateProduct(
+		r.Context(),
 		productID,
 		firebaseUID,
 		req.Name,
@@ -55,7 +57,17 @@ func (c *ProductUpdateController) HandleUpdateProduct(w http.ResponseWriter, r *
 		req.Price,
 	)
 	if err != nil {
-		c.respondError(w, http.StatusInternalServerError, err)
+		if c.respondIfBlocked(w, err) {
+			return
+		}
+		switch {
+		case errors.Is(err, usecase.ErrListingNotFound):
+			c.respondError(w, http.StatusNotFound, err)
+		case errors.Is(err, usecase.ErrNotProductOwner):
+			c.respondError(w, http.StatusForbidden, err)
+		default:
+			c.respondError(w, http.StatusInternalServerError, err)
+		}
 		return
 	}
 
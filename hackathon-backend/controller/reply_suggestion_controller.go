@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"errors"
+	"hackathon-backend/usecase"
+	"net/http"
+
+	"firebase.google.com/go/auth"
+)
+
+type ReplySuggestionController struct {
+	BaseController
+	Usecase *usecase.ReplySuggestionUsecase
+}
+
+func NewReplySuggestionController(u *usecase.ReplySuggestionUsecase, auth *auth.Client) *ReplySuggestionController {
+	return &ReplySuggestionController{
+		BaseController: BaseController{AuthClient: auth},
+		Usecase:        u,
+	}
+}
+
+// HandleSuggestReplies: GET /conversations/{partner}/suggested-replies
+// 返信の候補を返すだけで、メッセージは送信しません
+func (c *ReplySuggestionController) HandleSuggestReplies(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, err := c.verifyToken(r)
+	if err != nil {
+		c.respondError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	res, err := c.Usecase.SuggestReplies(r.Context(), firebaseUID, r.PathValue("partner"))
+	if errors.Is(err, usecase.ErrReplyPartnerNotFound) {
+		c.respondError(w, http.StatusNotFound, err)
+		return
+	}
+	if err != nil {
+		var invalid *usecase.AIOutputInvalidError
+		if errors.As(err, &invalid) {
+			c.respondJSON(w, http.StatusUnprocessableEntity, map[string]interface{}{
+				"error":  "AI could not produce valid output",
+				"fields": invalid.Fields,
+			})
+			return
+		}
+		c.respondError(w, http.StatusInternalServerError, err)
+		return
+	}
+	c.respondJSON(w, http.StatusOK, res)
+}
@@ -0,0 +1,112 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hackathon-backend/model"
+	"hackathon-backend/usecase"
+	"net/http"
+	"strconv"
+
+	"firebase.google.com/go/auth"
+)
+
+type ReportController struct {
+	BaseController
+	Usecase *usecase.ReportUsecase
+}
+
+func NewReportController(u *usecase.ReportUsecase, auth *auth.Client) *ReportController {
+	return &ReportController{
+		BaseController: BaseController{AuthClient: auth},
+		Usecase:        u,
+	}
+}
+
+// HandleCreateReport: POST /reports
+func (c *ReportController) HandleCreateReport(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, err := c.verifyToken(r)
+	if err != nil {
+		c.respondError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	var req model.CreateReportReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		c.respondError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.respondError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	report, err := c.Usecase.CreateReport(firebaseUID, req)
+	if err != nil {
+		c.respondReportError(w, err)
+		return
+	}
+
+	c.respondJSON(w, http.StatusCreated, report)
+}
+
+// HandleListReports: GET /moderation/reports?status=open&target_type=product&page=1
+// ルーターで RequireRole(moderator) を通してから呼ばれます
+func (c *ReportController) HandleListReports(w http.ResponseWriter, r *http.Request) {
+	status := r.URL.Query().Get("status")
+	targetType := r.URL.Query().Get("target_type")
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	if page < 1 {
+		page = 1
+	}
+	limit := 20
+
+	reports, err := c.Usecase.ListReports(status, targetType, page, limit)
+	if err != nil {
+		c.respondReportError(w, err)
+		return
+	}
+
+	c.respondJSON(w, http.StatusOK, reports)
+}
+
+// HandleUpdateReport: PUT /moderation/reports/{id}
+// ルーターで RequireRole(moderator) を通してから呼ばれます
+func (c *ReportController) HandleUpdateReport(w http.ResponseWriter, r *http.Request) {
+	reportID := r.PathValue("id")
+	if reportID == "" {
+		c.respondError(w, http.StatusBadRequest, fmt.Errorf("report id is required"))
+		return
+	}
+
+	var req model.UpdateReportReq
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		c.respondError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := req.Validate(); err != nil {
+		c.respondError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	report, err := c.Usecase.UpdateReport(currentUser(r), reportID, req)
+	if err != nil {
+		c.respondReportError(w, err)
+		return
+	}
+
+	c.respondJSON(w, http.StatusOK, report)
+}
+
+// usecase のエラーをステータスコードに変換する
+func (c *ReportController) respondReportError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrReportNotFound), errors.Is(err, usecase.ErrReportTargetNotFound):
+		c.respondError(w, http.StatusNotFound, err)
+	case errors.Is(err, usecase.ErrDuplicateReport), errors.Is(err, usecase.ErrInvalidReportStatus):
+		c.respondError(w, http.StatusConflict, err)
+	default:
+		c.respondError(w, http.StatusInternalServerError, err)
+	}
+}
@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"hackathon-backend/usecase"
+	"net/http"
+	"strconv"
+	"time"
+
+	"firebase.google.com/go/auth"
+)
+
+type SellerAnalyticsController struct {
+	BaseController
+	Usecase *usecase.SellerAnalyticsUsecase
+}
+
+func NewSellerAnalyticsController(u *usecase.SellerAnalyticsUsecase, auth *auth.Client) *SellerAnalyticsController {
+	return &SellerAnalyticsController{
+		BaseController: BaseController{AuthClient: auth},
+		Usecase:        u,
+	}
+}
+
+// HandleGetAnalytics: GET /users/me/analytics?from=2006-01-02&to=2006-01-02 (または ?days=7)
+// 期間の指定がなければ直近30日。to の日も含みます
+func (c *SellerAnalyticsController) HandleGetAnalytics(w http.ResponseWriter, r *http.Request) {
+	firebaseUID, err := c.verifyToken(r)
+	if err != nil {
+		c.respondError(w, http.StatusUnauthorized, err)
+		return
+	}
+
+	q := r.URL.Query()
+	y, m, d := time.Now().Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+
+	to := today.AddDate(0, 0, 1)
+	if s := q.Get("to"); s != "" {
+		t, err := time.ParseInLocation(time.DateOnly, s, time.Local)
+		if err != nil {
+			c.respondError(w, http.StatusBadRequest, fmt.Errorf("invalid to: %w", err))
+			return
+		}
+		to = t.AddDate(0, 0, 1)
+	}
+	days := 30
+	if s := q.Get("days"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 {
+			c.respondError(w, http.StatusBadRequest, fmt.Errorf("invalid days: %q", s))
+			return
+		}
+		days = n
+	}
+	from := to.AddDate(0, 0, -days)
+	if s := q.Get("from"); s != "" {
+		t, err := time.ParseInLocation(time.DateOnly, s, time.Local)
+		if err != nil {
+			c.respondError(w, http.StatusBadRequest, fmt.Errorf("invalid from: %w", err))
+			return
+		}
+		from = t
+	}
+	if !from.Before(to) {
+		c.respondError(w, http.StatusBadRequest, fmt.Errorf("from must be before to"))
+		return
+	}
+
+	res, err := c.Usecase.GetAnalytics(firebaseUID, from, to)
+	if errors.Is(err, usecase.ErrAnalyticsRangeTooLong) {
+		c.respondError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err != nil {
+		c.respondError(w, http.StatusInternalServerError, err)
+		return
+	}
+	c.respondJSON(w, http.StatusOK, res)
+}
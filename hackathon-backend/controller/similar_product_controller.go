@@ -0,0 +1,45 @@
+package controller
+
+import (
+	"database/sql"
+	"errors"
+	"hackathon-backend/usecase"
+	"net/http"
+	"strconv"
+
+	"firebase.google.com/go/auth"
+)
+
+type SimilarProductController struct {
+	BaseController
+	Usecase *usecase.SimilarProductUsecase
+}
+
+func NewSimilarProductController(u *usecase.SimilarProductUsecase, auth *auth.Client) *SimilarProductController {
+	return &SimilarProductController{
+		BaseController: BaseController{AuthClient: auth},
+		Usecase:        u,
+	}
+}
+
+// HandleGetSimilar: GET /products/{id}/similar?limit=12
+// 未ログインでも使えます (ログイン中なら is_liked が入る)
+func (c *SimilarProductController) HandleGetSimilar(w http.ResponseWriter, r *http.Request) {
+	viewerID := ""
+	if uid, err := c.verifyToken(r); err == nil {
+		viewerID = uid
+	}
+
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	res, err := c.Usecase.FindSimilar(r.Context(), r.PathValue("id"), viewerID, limit)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			c.respondError(w, http.StatusNotFound, err)
+			return
+		}
+		c.respondError(w, http.StatusInternalServerError, err)
+		return
+	}
+	c.respondJSON(w, http.StatusOK, res)
+}
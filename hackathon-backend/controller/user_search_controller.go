@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"hackathon-backend/usecase"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"firebase.google.com/go/auth"
 )
@@ -42,21 +44,47 @@ func (c *SearchUserController) HandleGetMe(w http.ResponseWriter, r *http.Reques
 	c.respondJSON(w, http.StatusOK, user)
 }
 
-// ★追加: GET /users/{id}
+// ★追加: GET /users/{id} (フォロー数・フォロワー数付き。Firebase UID や利用停止の理由などは返しません)
+// {id} はユーザーIDかハンドル (例: /users/01HXYZ... または /users/@taro)
 func (c *SearchUserController) HandleGetUser(w http.ResponseWriter, r *http.Request) {
 	// URLパラメータからIDを取得 (例: /users/01HXYZ...)
 	userID := r.PathValue("id")
 
-	user, err := c.Usecase.GetUserByID(userID)
+	// ログインしていれば is_following を入れる
+	viewerID := ""
+	if uid, err := c.verifyToken(r); err == nil {
+		viewerID = uid
+	}
+
+	user, err := c.Usecase.GetUserProfile(userID, viewerID)
 	if err != nil {
 		c.respondError(w, http.StatusInternalServerError, err)
 		return
 	}
 	if user == nil {
-		c.respondError(w, http.StatusNotFound, nil) // ユーザーがいない場合
+		c.respondError(w, http.StatusNotFound, fmt.Errorf("user not found"))
 		return
 	}
 
-	// 公開して良い情報だけ返すのが理想ですが、現状のUserモデル(名前, Bio, ID)ならそのまま返してOK
 	c.respondJSON(w, http.StatusOK, user)
 }
+
+// HandleSearch: GET /users?q=taro&page=1
+// 名前の部分一致・ハンドルの前方一致で探します (BANされたユーザー・退会したユーザーは出しません)
+func (c *SearchUserController) HandleSearch(w http.ResponseWriter, r *http.Request) {
+	q := strings.TrimSpace(r.URL.Query().Get("q"))
+	if q == "" {
+		c.respondError(w, http.StatusBadRequest, fmt.Errorf("q is required"))
+		return
+	}
+
+	page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	res, err := c.Usecase.SearchUsers(q, page, limit)
+	if err != nil {
+		c.respondError(w, http.StatusInternalServerError, err)
+		return
+	}
+	c.respondJSON(w, http.StatusOK, res)
+}
@@ -1,6 +1,9 @@
 package controller
 
 import (
+	"errors"
+	"fmt"
+	"hackathon-backend/model"
 	"hackathon-backend/usecase"
 	"net/http"
 
@@ -41,6 +44,23 @@ func (c *UserUpdateController) HandleUpdate(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	// 表示言語は送られてきたときだけ更新する (空文字で未設定に戻す)
+	var preferredLanguage *string
+	if values, ok := r.MultipartForm.Value["preferred_language"]; ok {
+		lang := values[0]
+		if lang != "" && !model.IsValidLanguage(lang) {
+			c.respondError(w, http.StatusBadRequest, fmt.Errorf("unsupported preferred_language: %q", lang))
+			return
+		}
+		preferredLanguage = &lang
+	}
+
+	// ハンドルも送られてきたときだけ更新する (空文字で未設定に戻す)
+	var handle *string
+	if values, ok := r.MultipartForm.Value["handle"]; ok {
+		handle = &values[0]
+	}
+
 	// ★ 画像ファイルの取得
 	file, header, err := r.FormFile("image")
 	// ファイルがない場合は err が返るが、画像なし更新も許可したいのでチェック
@@ -57,8 +77,15 @@ func (c *UserUpdateController) HandleUpdate(w http.ResponseWriter, r *http.Reque
 	}
 
 	// UseCase 呼び出し
-	user, err := c.Usecase.UpdateUser(r.Context(), firebaseUID, name, bio, header)
-	if err != nil {
+	user, err := c.Usecase.UpdateUser(r.Context(), firebaseUID, name, bio, preferredLanguage, handle, header)
+	switch {
+	case errors.Is(err, usecase.ErrInvalidHandle):
+		c.respondError(w, http.StatusBadRequest, err)
+		return
+	case errors.Is(err, usecase.ErrHandleTaken):
+		c.respondError(w, http.StatusConflict, err)
+		return
+	case err != nil:
 		c.respondError(w, http.StatusInternalServerError, err)
 		return
 	}
@@ -0,0 +1,120 @@
+package dao
+
+import (
+	"database/sql"
+	"hackathon-backend/model"
+	"time"
+)
+
+// AccountDao: 退会の処理 (複数のテーブルをまとめて更新する)
+type AccountDao struct {
+	db *sql.DB
+}
+
+func NewAccountDao(db *sql.DB) *AccountDao {
+	return &AccountDao{db: db}
+}
+
+// RecentTradeIDs: since 以降に売れた・買った商品のID (退会前に取引中かどうかを見るため)
+func (d *AccountDao) RecentTradeIDs(userID string, since time.Time) ([]string, error) {
+	query := `
+		SELECT id FROM products
+		WHERE (user_id = ? OR buyer_id = ?) AND sold_at >= ?
+		ORDER BY sold_at DESC
+	`
+	rows, err := d.db.Query(query, userID, userID, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// Anonymize: ユーザーを退会させる
+//   - プロフィールを匿名化し、Firebase のアカウントとの紐付けを外す
+//   - 売れていない出品は取り下げて画像を外す (売れた商品は購入者の記録として残す)
+//   - いいね・住所録・フォロー・お知らせ・検索履歴を削除し、閲覧の記録からユーザーを外す
+//
+// メッセージは相手の記録として残します。ストレージから消すべき画像のURLを返します
+func (d *AccountDao) Anonymize(userID string) ([]string, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	// 1. 消す画像を集める (プロフィール画像と、取り下げる出品の画像)
+	rows, err := tx.Query(`
+		SELECT image_url FROM users WHERE id = ? AND image_url IS NOT NULL AND image_url <> ''
+		UNION ALL
+		SELECT image_url FROM products WHERE user_id = ? AND buyer_id IS NULL AND image_url IS NOT NULL AND image_url <> ''
+	`, userID, userID)
+	if err != nil {
+		return nil, err
+	}
+	var images []string
+	for rows.Next() {
+		var url string
+		if err := rows.Scan(&url); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		images = append(images, url)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	statements := []struct {
+		query string
+		args  []interface{}
+	}{
+		// 2. 売れていない出品を取り下げる
+		{`UPDATE products SET taken_down_at = COALESCE(taken_down_at, NOW()), image_url = NULL
+		  WHERE user_id = ? AND buyer_id IS NULL`, []interface{}{userID}},
+		// 3. いいねを外す (いいね数も減らす)
+		{`UPDATE products p JOIN likes l ON l.product_id = p.id
+		  SET p.like_count = GREATEST(p.like_count - 1, 0)
+		  WHERE l.user_id = ?`, []interface{}{userID}},
+		{`DELETE FROM likes WHERE user_id = ?`, []interface{}{userID}},
+		// 4. 住所録・フォロー・お知らせ・検索履歴 (購入時の届け先の写しも消す)
+		{`DELETE FROM addresses WHERE user_id = ?`, []interface{}{userID}},
+		{`UPDATE sale_shipping_addresses SET ` + redactShippingColumns + ` WHERE buyer_id = ? AND redacted_at IS NULL`, []interface{}{userID}},
+		{`DELETE FROM follows WHERE follower_id = ? OR followee_id = ?`, []interface{}{userID, userID}},
+		{`DELETE FROM notifications WHERE user_id = ? OR actor_id = ?`, []interface{}{userID, userID}},
+		{`DELETE FROM search_history WHERE user_id = ?`, []interface{}{userID}},
+		// 5. 閲覧・いいねの記録は急上昇の計算に使うので、ユーザーだけ外す
+		{`UPDATE product_events SET user_id = NULL, viewer_key = NULL WHERE user_id = ?`, []interface{}{userID}},
+	}
+	for _, st := range statements {
+		if _, err := tx.Exec(st.query, st.args...); err != nil {
+			return nil, err
+		}
+	}
+
+	// 6. プロフィールの匿名化 (行は商品・メッセージから参照されているので残す)
+	_, err = tx.Exec(`
+		UPDATE users
+		SET name = ?, handle = NULL, bio = NULL, image_url = NULL, preferred_language = NULL,
+		    firebase_uid = ?, status = ?, status_reason = NULL, suspended_until = NULL
+		WHERE id = ?
+	`, model.DeletedUserName, "deleted:"+userID, model.UserStatusDeleted, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return images, nil
+}
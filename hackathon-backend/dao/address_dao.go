@@ -0,0 +1,170 @@
+package dao
+
+import (
+	"database/sql"
+	"hackathon-backend/model"
+	"time"
+)
+
+type AddressDao struct {
+	db *sql.DB
+}
+
+func NewAddressDao(db *sql.DB) *AddressDao {
+	return &AddressDao{db: db}
+}
+
+// ListByUserID: 住所録 (いつも使う住所を先頭に、登録順)
+func (d *AddressDao) ListByUserID(userID string) ([]*model.Address, error) {
+	query := `
+		SELECT id, name, postal_code, prefecture, city, line1, line2, phone, is_default, created_at
+		FROM addresses
+		WHERE user_id = ?
+		ORDER BY is_default DESC, created_at ASC
+	`
+	rows, err := d.db.Query(query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	addresses := []*model.Address{}
+	for rows.Next() {
+		a := &model.Address{}
+		if err := rows.Scan(&a.ID, &a.Name, &a.PostalCode, &a.Prefecture, &a.City, &a.Line1, &a.Line2, &a.Phone, &a.IsDefault, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		addresses = append(addresses, a)
+	}
+	return addresses, rows.Err()
+}
+
+// FindByID: 本人の住所を1件取得 (他人の住所や、見つからなければ nil, nil)
+func (d *AddressDao) FindByID(id, userID string) (*model.Address, error) {
+	query := `
+		SELECT id, name, postal_code, prefecture, city, line1, line2, phone, is_default, created_at
+		FROM addresses
+		WHERE id = ? AND user_id = ?
+	`
+	a := &model.Address{}
+	err := d.db.QueryRow(query, id, userID).Scan(&a.ID, &a.Name, &a.PostalCode, &a.Prefecture, &a.City, &a.Line1, &a.Line2, &a.Phone, &a.IsDefault, &a.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// CountByUserID: 登録している住所の数
+func (d *AddressDao) CountByUserID(userID string) (int, error) {
+	var count int
+	err := d.db.QueryRow("SELECT COUNT(*) FROM addresses WHERE user_id = ?", userID).Scan(&count)
+	return count, err
+}
+
+// Create: 住所を登録 (IsDefault なら他の住所の is_default を外す)
+func (d *AddressDao) Create(userID string, a *model.Address) error {
+	return d.withDefault(userID, a.IsDefault, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			INSERT INTO addresses (id, user_id, name, postal_code, prefecture, city, line1, line2, phone, is_default, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		`, a.ID, userID, a.Name, a.PostalCode, a.Prefecture, a.City, a.Line1, a.Line2, a.Phone, a.IsDefault, a.CreatedAt)
+		return err
+	})
+}
+
+// Update: 住所を更新 (IsDefault なら他の住所の is_default を外す)
+func (d *AddressDao) Update(userID string, a *model.Address) error {
+	return d.withDefault(userID, a.IsDefault, func(tx *sql.Tx) error {
+		_, err := tx.Exec(`
+			UPDATE addresses
+			SET name = ?, postal_code = ?, prefecture = ?, city = ?, line1 = ?, line2 = ?, phone = ?, is_default = ?
+			WHERE id = ? AND user_id = ?
+		`, a.Name, a.PostalCode, a.Prefecture, a.City, a.Line1, a.Line2, a.Phone, a.IsDefault, a.ID, userID)
+		return err
+	})
+}
+
+// Delete: 住所を削除 (見つからなければ sql.ErrNoRows)
+// いつも使う住所を消したときは、残りのうち一番古い住所を「いつも使う」にします
+// 購入時の届け先は写しなので、住所録から消しても取引には影響しません
+func (d *AddressDao) Delete(id, userID string) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var isDefault bool
+	err = tx.QueryRow("SELECT is_default FROM addresses WHERE id = ? AND user_id = ? FOR UPDATE", id, userID).Scan(&isDefault)
+	if err != nil {
+		return err // 見つからなければ sql.ErrNoRows
+	}
+	if _, err := tx.Exec("DELETE FROM addresses WHERE id = ? AND user_id = ?", id, userID); err != nil {
+		return err
+	}
+	if isDefault {
+		query := `UPDATE addresses SET is_default = TRUE WHERE user_id = ? ORDER BY created_at ASC, id ASC LIMIT 1`
+		if _, err := tx.Exec(query, userID); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// いつも使う住所は1件だけにする
+func (d *AddressDao) withDefault(userID string, isDefault bool, fn func(tx *sql.Tx) error) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if isDefault {
+		if _, err := tx.Exec("UPDATE addresses SET is_default = FALSE WHERE user_id = ?", userID); err != nil {
+			return err
+		}
+	}
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// FindSaleShipping: 売れた商品の届け先 (なければ nil, nil)
+func (d *AddressDao) FindSaleShipping(productID string) (*model.SaleShipping, error) {
+	query := `
+		SELECT product_id, buyer_id, name, postal_code, prefecture, city, line1, line2, phone, created_at, redacted_at IS NOT NULL
+		FROM sale_shipping_addresses
+		WHERE product_id = ?
+	`
+	s := &model.SaleShipping{}
+	err := d.db.QueryRow(query, productID).Scan(&s.ProductID, &s.BuyerID, &s.Name, &s.PostalCode, &s.Prefecture, &s.City, &s.Line1, &s.Line2, &s.Phone, &s.CreatedAt, &s.Redacted)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// RedactSaleShippingOlderThan: 売れてから age 以上経った商品の届け先を消す (消した件数を返す)
+// created_at はDBの時刻で入るので、境目もDBの時刻で計算します
+func (d *AddressDao) RedactSaleShippingOlderThan(age time.Duration) (int64, error) {
+	query := `
+		UPDATE sale_shipping_addresses
+		SET ` + redactShippingColumns + `
+		WHERE redacted_at IS NULL AND created_at < NOW() - INTERVAL ? SECOND
+	`
+	result, err := d.db.Exec(query, int64(age/time.Second))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// 届け先を消すときの SET 句
+const redactShippingColumns = `name = '', postal_code = '', prefecture = '', city = '', line1 = '', line2 = '', phone = '', redacted_at = NOW()`
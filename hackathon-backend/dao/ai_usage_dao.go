@@ -0,0 +1,85 @@
+package dao
+
+import (
+	"database/sql"
+	"hackathon-backend/model"
+	"time"
+)
+
+type AIUsageDao struct {
+	db *sql.DB
+}
+
+func NewAIUsageDao(db *sql.DB) *AIUsageDao {
+	return &AIUsageDao{db: db}
+}
+
+// Create: 使用量を保存
+func (d *AIUsageDao) Create(u *model.AIUsage) error {
+	query := `
+		INSERT INTO ai_usage_logs (id, user_id, endpoint, model, status, latency_ms, calls, prompt_tokens, completion_tokens, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := d.db.Exec(query, u.ID, u.UserID, u.Endpoint, u.Model, u.Status, u.LatencyMs, u.Calls, u.PromptTokens, u.CompletionTokens, u.CreatedAt)
+	return err
+}
+
+// ReserveDaily: day の利用回数を1つ増やす (limit に達していたら増やさずに false を返す)
+// 確認と加算を1つの UPDATE で行うので、同時に呼ばれても limit を超えません。増やした後の回数を返します
+func (d *AIUsageDao) ReserveDaily(userID, day string, limit int) (int, bool, error) {
+	if _, err := d.db.Exec(`INSERT IGNORE INTO ai_daily_quota (user_id, day, used) VALUES (?, ?, 0)`, userID, day); err != nil {
+		return 0, false, err
+	}
+	// LAST_INSERT_ID(expr) で更新後の値を受け取る
+	result, err := d.db.Exec(`
+		UPDATE ai_daily_quota SET used = LAST_INSERT_ID(used + 1)
+		WHERE user_id = ? AND day = ? AND used < ?
+	`, userID, day, limit)
+	if err != nil {
+		return 0, false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil || n == 0 {
+		return 0, false, err
+	}
+	used, err := result.LastInsertId()
+	if err != nil {
+		return 0, false, err
+	}
+	return int(used), true, nil
+}
+
+// RefundDaily: ReserveDaily で確保した1回分を戻す (AIを呼ばずに終わったリクエスト用)
+func (d *AIUsageDao) RefundDaily(userID, day string) error {
+	_, err := d.db.Exec(`UPDATE ai_daily_quota SET used = used - 1 WHERE user_id = ? AND day = ? AND used > 0`, userID, day)
+	return err
+}
+
+// SumByUser: 期間内の使用量をユーザーごとに集計 (トークン数の多い順)
+// 利用額は料金設定に依存するので usecase 側で計算します
+func (d *AIUsageDao) SumByUser(from, to time.Time, limit int) ([]*model.AISpendByUser, error) {
+	query := `
+		SELECT a.user_id, COALESCE(u.name, ''), COUNT(*), SUM(a.calls), SUM(a.prompt_tokens), SUM(a.completion_tokens)
+		FROM ai_usage_logs a
+		LEFT JOIN users u ON a.user_id = u.id
+		WHERE a.created_at >= ? AND a.created_at < ?
+		GROUP BY a.user_id, u.name
+		ORDER BY SUM(a.prompt_tokens) + SUM(a.completion_tokens) DESC
+		LIMIT ?
+	`
+	rows, err := d.db.Query(query, from, to, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var spends []*model.AISpendByUser
+	for rows.Next() {
+		s := &model.AISpendByUser{}
+		if err := rows.Scan(&s.UserID, &s.UserName, &s.Requests, &s.Calls, &s.PromptTokens, &s.CompletionTokens); err != nil {
+			return nil, err
+		}
+		spends = append(spends, s)
+	}
+	return spends, nil
+}
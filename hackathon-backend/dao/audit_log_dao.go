@@ -0,0 +1,80 @@
+package dao
+
+import (
+	"database/sql"
+	"hackathon-backend/model"
+)
+
+type AuditLogDao struct {
+	db *sql.DB
+}
+
+func NewAuditLogDao(db *sql.DB) *AuditLogDao {
+	return &AuditLogDao{db: db}
+}
+
+// Begin: 管理操作と監査ログを一緒に書くトランザクションを開始
+func (d *AuditLogDao) Begin() (*sql.Tx, error) {
+	return d.db.Begin()
+}
+
+// Create: 監査ログを保存 (管理操作と同じトランザクションで書く)
+func (d *AuditLogDao) Create(tx *sql.Tx, l *model.AuditLog) error {
+	query := `
+		INSERT INTO admin_audit_logs (id, actor_id, action, target_type, target_id, detail, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := tx.Exec(query, l.ID, l.ActorID, l.Action, l.TargetType, l.TargetID, l.Detail, l.CreatedAt)
+	return err
+}
+
+// 一覧用の検索条件を作成するヘルパー
+func (d *AuditLogDao) buildListCondition(actorID, targetType, targetID string) (string, []interface{}) {
+	query := ` FROM admin_audit_logs WHERE 1=1 `
+	var args []interface{}
+
+	if actorID != "" {
+		query += " AND actor_id = ? "
+		args = append(args, actorID)
+	}
+	if targetType != "" {
+		query += " AND target_type = ? "
+		args = append(args, targetType)
+	}
+	if targetID != "" {
+		query += " AND target_id = ? "
+		args = append(args, targetID)
+	}
+	return query, args
+}
+
+// List: 監査ログを新しい順に取得
+func (d *AuditLogDao) List(actorID, targetType, targetID string, limit, offset int) ([]*model.AuditLog, error) {
+	whereQuery, args := d.buildListCondition(actorID, targetType, targetID)
+	query := `SELECT id, actor_id, action, target_type, target_id, COALESCE(detail, ''), created_at ` +
+		whereQuery + ` ORDER BY created_at DESC LIMIT ? OFFSET ? `
+	args = append(args, limit, offset)
+
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var logs []*model.AuditLog
+	for rows.Next() {
+		l := &model.AuditLog{}
+		if err := rows.Scan(&l.ID, &l.ActorID, &l.Action, &l.TargetType, &l.TargetID, &l.Detail, &l.CreatedAt); err != nil {
+			return nil, err
+		}
+		logs = append(logs, l)
+	}
+	return logs, nil
+}
+
+func (d *AuditLogDao) Count(actorID, targetType, targetID string) (int, error) {
+	whereQuery, args := d.buildListCondition(actorID, targetType, targetID)
+	var count int
+	err := d.db.QueryRow(`SELECT COUNT(*) `+whereQuery, args...).Scan(&count)
+	return count, err
+}
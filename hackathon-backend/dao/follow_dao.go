@@ -0,0 +1,109 @@
+package dao
+
+import (
+	"database/sql"
+	"hackathon-backend/model"
+)
+
+type FollowDao struct {
+	db *sql.DB
+}
+
+func NewFollowDao(db *sql.DB) *FollowDao {
+	return &FollowDao{db: db}
+}
+
+// Follow: フォローする (フォロー済みなら何もしない)
+func (d *FollowDao) Follow(followerID, followeeID string) error {
+	_, err := d.db.Exec("INSERT IGNORE INTO follows (follower_id, followee_id) VALUES (?, ?)", followerID, followeeID)
+	return err
+}
+
+// Unfollow: フォローを外す (フォローしていなければ何もしない)
+func (d *FollowDao) Unfollow(followerID, followeeID string) error {
+	_, err := d.db.Exec("DELETE FROM follows WHERE follower_id = ? AND followee_id = ?", followerID, followeeID)
+	return err
+}
+
+func (d *FollowDao) IsFollowing(followerID, followeeID string) (bool, error) {
+	var count int
+	err := d.db.QueryRow("SELECT COUNT(*) FROM follows WHERE follower_id = ? AND followee_id = ?", followerID, followeeID).Scan(&count)
+	return count > 0, err
+}
+
+// CountFollowers: フォロワー数
+func (d *FollowDao) CountFollowers(userID string) (int, error) {
+	var count int
+	err := d.db.QueryRow("SELECT COUNT(*) FROM follows WHERE followee_id = ?", userID).Scan(&count)
+	return count, err
+}
+
+// CountFollowing: フォロー数
+func (d *FollowDao) CountFollowing(userID string) (int, error) {
+	var count int
+	err := d.db.QueryRow("SELECT COUNT(*) FROM follows WHERE follower_id = ?", userID).Scan(&count)
+	return count, err
+}
+
+// ListFollowers: フォロワー一覧 (フォローされた新しい順)
+func (d *FollowDao) ListFollowers(userID string, limit, offset int) ([]*model.FollowUser, error) {
+	query := `
+		SELECT u.id, u.name, COALESCE(u.handle, ''), COALESCE(u.image_url, ''), COALESCE(u.bio, ''), f.created_at
+		FROM follows f
+		JOIN users u ON f.follower_id = u.id
+		WHERE f.followee_id = ?
+		ORDER BY f.created_at DESC
+		LIMIT ? OFFSET ?
+	`
+	return d.fetchFollowUsers(query, userID, limit, offset)
+}
+
+// ListFollowing: フォロー中のユーザー一覧 (フォローした新しい順)
+func (d *FollowDao) ListFollowing(userID string, limit, offset int) ([]*model.FollowUser, error) {
+	query := `
+		SELECT u.id, u.name, COALESCE(u.handle, ''), COALESCE(u.image_url, ''), COALESCE(u.bio, ''), f.created_at
+		FROM follows f
+		JOIN users u ON f.followee_id = u.id
+		WHERE f.follower_id = ?
+		ORDER BY f.created_at DESC
+		LIMIT ? OFFSET ?
+	`
+	return d.fetchFollowUsers(query, userID, limit, offset)
+}
+
+// FollowingIDs: フォロー中のユーザーのID
+func (d *FollowDao) FollowingIDs(userID string) ([]string, error) {
+	rows, err := d.db.Query("SELECT followee_id FROM follows WHERE follower_id = ?", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (d *FollowDao) fetchFollowUsers(query string, args ...interface{}) ([]*model.FollowUser, error) {
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []*model.FollowUser{}
+	for rows.Next() {
+		u := &model.FollowUser{}
+		if err := rows.Scan(&u.ID, &u.Name, &u.Handle, &u.ImageURL, &u.Bio, &u.FollowedAt); err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	return users, nil
+}
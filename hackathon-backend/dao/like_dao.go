@@ -12,19 +12,39 @@ func NewLikeDao(db *sql.DB) *LikeDao {
 	return &LikeDao{db: db}
 }
 
-// AddLike: いいねを追加
-func (d *LikeDao) AddLike(userID, productID string) error {
-	// IGNORE: 既にいいね済みならエラーにせず無視
-	query := "INSERT IGNORE INTO likes (user_id, product_id) VALUES (?, ?)"
-	_, err := d.db.Exec(query, userID, productID)
-	return err
+// AddLike: いいねを追加し、商品のいいね数を増やす (いいね済みなら何もしない)
+// 戻り値: 追加されたか, 更新後のいいね数
+func (d *LikeDao) AddLike(userID, productID string) (bool, int, error) {
+	return d.withProductLock(productID, func(tx *sql.Tx) (bool, error) {
+		return d.addLike(tx, userID, productID)
+	})
+}
+
+// RemoveLike: いいねを解除し、商品のいいね数を減らす (いいねしていなければ何もしない)
+// 戻り値: 解除されたか, 更新後のいいね数
+func (d *LikeDao) RemoveLike(userID, productID string) (bool, int, error) {
+	return d.withProductLock(productID, func(tx *sql.Tx) (bool, error) {
+		return d.removeLike(tx, userID, productID)
+	})
 }
 
-// RemoveLike: いいねを解除
-func (d *LikeDao) RemoveLike(userID, productID string) error {
-	query := "DELETE FROM likes WHERE user_id = ? AND product_id = ?"
-	_, err := d.db.Exec(query, userID, productID)
-	return err
+// ToggleLike: いいねを切り替える (状態の確認と更新を1つのトランザクションで行う)
+// 戻り値: 切り替え後にいいねしているか, 更新後のいいね数
+func (d *LikeDao) ToggleLike(userID, productID string) (bool, int, error) {
+	var liked bool
+	_, count, err := d.withProductLock(productID, func(tx *sql.Tx) (bool, error) {
+		var exists int
+		err := tx.QueryRow("SELECT COUNT(*) FROM likes WHERE user_id = ? AND product_id = ?", userID, productID).Scan(&exists)
+		if err != nil {
+			return false, err
+		}
+		if exists > 0 {
+			return d.removeLike(tx, userID, productID)
+		}
+		liked = true
+		return d.addLike(tx, userID, productID)
+	})
+	return liked, count, err
 }
 
 // HasLiked: 自分がいいねしているか確認
@@ -38,3 +58,59 @@ func (d *LikeDao) HasLiked(userID, productID string) (bool, error) {
 	// 1件以上あれば true
 	return count > 0, nil
 }
+
+func (d *LikeDao) addLike(tx *sql.Tx, userID, productID string) (bool, error) {
+	// IGNORE: 既にいいね済みならエラーにせず無視
+	result, err := tx.Exec("INSERT IGNORE INTO likes (user_id, product_id) VALUES (?, ?)", userID, productID)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil || n == 0 {
+		return false, err
+	}
+	_, err = tx.Exec("UPDATE products SET like_count = like_count + 1 WHERE id = ?", productID)
+	return err == nil, err
+}
+
+func (d *LikeDao) removeLike(tx *sql.Tx, userID, productID string) (bool, error) {
+	result, err := tx.Exec("DELETE FROM likes WHERE user_id = ? AND product_id = ?", userID, productID)
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	if err != nil || n == 0 {
+		return false, err
+	}
+	_, err = tx.Exec("UPDATE products SET like_count = like_count - 1 WHERE id = ? AND like_count > 0", productID)
+	return err == nil, err
+}
+
+// 商品の行をロックしてから fn を実行する (同じ商品へのいいねを順番に処理する)
+// 商品がなければ sql.ErrNoRows を返します
+func (d *LikeDao) withProductLock(productID string, fn func(tx *sql.Tx) (bool, error)) (bool, int, error) {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return false, 0, err
+	}
+	defer tx.Rollback()
+
+	var locked int
+	if err := tx.QueryRow("SELECT 1 FROM products WHERE id = ? FOR UPDATE", productID).Scan(&locked); err != nil {
+		return false, 0, err
+	}
+
+	changed, err := fn(tx)
+	if err != nil {
+		return false, 0, err
+	}
+
+	var count int
+	if err := tx.QueryRow("SELECT like_count FROM products WHERE id = ?", productID).Scan(&count); err != nil {
+		return false, 0, err
+	}
+	if err := tx.Commit(); err != nil {
+		return false, 0, err
+	}
+	return changed, count, nil
+}
@@ -23,6 +23,35 @@ func (d *MessageDao) Create(msg *model.Message) error {
 	return err
 }
 
+// FindByID: メッセージを1件取得 (見つからなければ nil, nil)
+func (d *MessageDao) FindByID(id string) (*model.Message, error) {
+	query := `
+	   SELECT
+           m.id, m.sender_id, m.receiver_id, m.content, m.created_at,
+           m.product_id, p.name, m.is_read, m.is_deleted
+       FROM messages m
+       LEFT JOIN products p ON m.product_id = p.id
+       WHERE m.id = ?
+	`
+	m := &model.Message{}
+	var productID sql.NullString
+	var productName sql.NullString
+	err := d.db.QueryRow(query, id).Scan(&m.ID, &m.SenderID, &m.ReceiverID, &m.Content, &m.CreatedAt, &productID, &productName, &m.IsRead, &m.IsDeleted)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if productID.Valid {
+		m.ProductID = productID.String
+	}
+	if productName.Valid {
+		m.ProductName = productName.String
+	}
+	return m, nil
+}
+
 // GetMessagesBetween: 2人の間のメッセージを時系列順に取得
 func (d *MessageDao) GetMessagesBetween(userA, userB string) ([]*model.Message, error) {
 	// Aが送ってBが受け取った or Bが送ってAが受け取った メッセージを取得
@@ -0,0 +1,59 @@
+package dao
+
+import (
+	"database/sql"
+	"hackathon-backend/model"
+	"strings"
+)
+
+type MessageTranslationDao struct {
+	db *sql.DB
+}
+
+func NewMessageTranslationDao(db *sql.DB) *MessageTranslationDao {
+	return &MessageTranslationDao{db: db}
+}
+
+// FindByMessageIDs: 翻訳済みのものをメッセージIDをキーにして返す
+func (d *MessageTranslationDao) FindByMessageIDs(messageIDs []string, language string) (map[string]*model.MessageTranslation, error) {
+	result := make(map[string]*model.MessageTranslation)
+	if len(messageIDs) == 0 {
+		return result, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(messageIDs)), ",")
+	query := `
+		SELECT message_id, language, source_language, translated_text
+		FROM message_translations
+		WHERE language = ? AND message_id IN (` + placeholders + `)
+	`
+	args := []interface{}{language}
+	for _, id := range messageIDs {
+		args = append(args, id)
+	}
+
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		t := &model.MessageTranslation{}
+		if err := rows.Scan(&t.MessageID, &t.Language, &t.SourceLanguage, &t.Text); err != nil {
+			return nil, err
+		}
+		result[t.MessageID] = t
+	}
+	return result, nil
+}
+
+// Create: 翻訳結果を保存 (同時に翻訳された場合は先に保存された方を残す)
+func (d *MessageTranslationDao) Create(t *model.MessageTranslation) error {
+	query := `
+		INSERT IGNORE INTO message_translations (message_id, language, source_language, translated_text)
+		VALUES (?, ?, ?, ?)
+	`
+	_, err := d.db.Exec(query, t.MessageID, t.Language, t.SourceLanguage, t.Text)
+	return err
+}
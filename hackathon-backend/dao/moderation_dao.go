@@ -0,0 +1,97 @@
+package dao
+
+import (
+	"database/sql"
+	"encoding/json"
+	"hackathon-backend/model"
+)
+
+type ModerationDao struct {
+	db *sql.DB
+}
+
+func NewModerationDao(db *sql.DB) *ModerationDao {
+	return &ModerationDao{db: db}
+}
+
+// Create: 審査結果を保存 (categories, reasons は JSON 配列で保存。content は空なら NULL)
+func (d *ModerationDao) Create(r *model.ModerationResult) error {
+	categories, err := json.Marshal(r.Categories)
+	if err != nil {
+		return err
+	}
+	reasons, err := json.Marshal(r.Reasons)
+	if err != nil {
+		return err
+	}
+	query := `
+		INSERT INTO moderation_results (id, target_type, target_id, author_id, decision, categories, reasons, content, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	var content sql.NullString
+	if r.Content != "" {
+		content = sql.NullString{String: r.Content, Valid: true}
+	}
+	_, err = d.db.Exec(query, r.ID, r.TargetType, r.TargetID, r.AuthorID, r.Decision, string(categories), string(reasons), content, r.CreatedAt)
+	return err
+}
+
+// 一覧用の検索条件を作成するヘルパー
+func (d *ModerationDao) buildListCondition(decision, targetType, targetID string) (string, []interface{}) {
+	query := ` FROM moderation_results WHERE 1=1 `
+	var args []interface{}
+
+	if decision != "" {
+		query += " AND decision = ? "
+		args = append(args, decision)
+	}
+	if targetType != "" {
+		query += " AND target_type = ? "
+		args = append(args, targetType)
+	}
+	if targetID != "" {
+		query += " AND target_id = ? "
+		args = append(args, targetID)
+	}
+	return query, args
+}
+
+// List: 審査結果を新しい順に取得
+func (d *ModerationDao) List(decision, targetType, targetID string, limit, offset int) ([]*model.ModerationResult, error) {
+	whereQuery, args := d.buildListCondition(decision, targetType, targetID)
+	query := `SELECT id, target_type, target_id, author_id, decision, categories, reasons, content, created_at ` +
+		whereQuery + ` ORDER BY created_at DESC LIMIT ? OFFSET ? `
+	args = append(args, limit, offset)
+
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*model.ModerationResult
+	for rows.Next() {
+		r := &model.ModerationResult{}
+		var categories, reasons string
+		var content sql.NullString
+		if err := rows.Scan(&r.ID, &r.TargetType, &r.TargetID, &r.AuthorID, &r.Decision, &categories, &reasons, &content, &r.CreatedAt); err != nil {
+			return nil, err
+		}
+		r.Content = content.String
+		if err := json.Unmarshal([]byte(categories), &r.Categories); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(reasons), &r.Reasons); err != nil {
+			return nil, err
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+func (d *ModerationDao) Count(decision, targetType, targetID string) (int, error) {
+	whereQuery, args := d.buildListCondition(decision, targetType, targetID)
+	var count int
+	err := d.db.QueryRow(`SELECT COUNT(*) `+whereQuery, args...).Scan(&count)
+	return count, err
+}
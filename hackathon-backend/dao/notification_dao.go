@@ -0,0 +1,79 @@
+package dao
+
+import (
+	"database/sql"
+	"hackathon-backend/model"
+)
+
+type NotificationDao struct {
+	db *sql.DB
+}
+
+func NewNotificationDao(db *sql.DB) *NotificationDao {
+	return &NotificationDao{db: db}
+}
+
+// CreateForFollowers: actorID のフォロワー全員にお知らせを作る (作った件数を返す)
+func (d *NotificationDao) CreateForFollowers(actorID, notificationType, productID string) (int64, error) {
+	query := `
+		INSERT INTO notifications (user_id, type, actor_id, product_id)
+		SELECT f.follower_id, ?, f.followee_id, NULLIF(?, '')
+		FROM follows f
+		WHERE f.followee_id = ?
+	`
+	result, err := d.db.Exec(query, notificationType, productID, actorID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// List: お知らせ一覧 (新しい順。非表示にされた・削除された商品のお知らせは出さない)
+func (d *NotificationDao) List(userID string, limit, offset int) ([]*model.Notification, error) {
+	query := `
+		SELECT n.id, n.type, n.actor_id, COALESCE(u.name, ''), COALESCE(u.image_url, ''),
+		       COALESCE(n.product_id, ''), COALESCE(p.name, ''), n.is_read, n.created_at
+		FROM notifications n
+		LEFT JOIN users u ON n.actor_id = u.id
+		LEFT JOIN products p ON n.product_id = p.id
+		WHERE n.user_id = ? AND ` + visibleNotificationCondition + `
+		ORDER BY n.created_at DESC, n.id DESC
+		LIMIT ? OFFSET ?
+	`
+	rows, err := d.db.Query(query, userID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := []*model.Notification{}
+	for rows.Next() {
+		n := &model.Notification{}
+		if err := rows.Scan(&n.ID, &n.Type, &n.ActorID, &n.ActorName, &n.ActorImageURL, &n.ProductID, &n.ProductName, &n.IsRead, &n.CreatedAt); err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+	return notifications, nil
+}
+
+// CountUnread: 未読の数 (List に出るものだけ数える)
+func (d *NotificationDao) CountUnread(userID string) (int, error) {
+	var count int
+	query := `
+		SELECT COUNT(*)
+		FROM notifications n
+		LEFT JOIN products p ON n.product_id = p.id
+		WHERE n.user_id = ? AND n.is_read = FALSE AND ` + visibleNotificationCondition
+	err := d.db.QueryRow(query, userID).Scan(&count)
+	return count, err
+}
+
+// お知らせは出品と同時に作るので、後から非表示にされた・削除された商品のものはここで除く
+const visibleNotificationCondition = `(n.product_id IS NULL OR (p.id IS NOT NULL AND p.taken_down_at IS NULL))`
+
+// MarkAllRead: すべて既読にする
+func (d *NotificationDao) MarkAllRead(userID string) error {
+	_, err := d.db.Exec("UPDATE notifications SET is_read = TRUE WHERE user_id = ? AND is_read = FALSE", userID)
+	return err
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"fmt"
 	"hackathon-backend/model"
+	"strings"
 )
 
 type ProductDao struct {
@@ -32,7 +33,7 @@ func (d *ProductDao) Create(product *model.Product) error {
 }
 
 // 共通の検索条件（WHERE句とARGS）を作成するヘルパー
-func (d *ProductDao) buildSearchCondition(keyword, status, targetUserID string) (string, []interface{}) {
+func (d *ProductDao) buildSearchCondition(f model.ProductFilter) (string, []interface{}) {
 	// u: 出品者, u2: 購入者
 	query := ` FROM products p 
 	           JOIN users u ON p.user_id = u.id 
@@ -40,25 +41,62 @@ func (d *ProductDao) buildSearchCondition(keyword, status, targetUserID string)
 	           WHERE 1=1 `
 	var args []interface{}
 
-	if targetUserID != "" {
+	// 運営が取り下げた商品は検索結果に出さない
+	query += " AND p.taken_down_at IS NULL "
+
+	// 利用停止中・BAN中の出品者の商品は検索結果に出さない
+	query += ` AND (u.status = 'active'
+	               OR (u.status = 'suspended' AND u.suspended_until IS NOT NULL AND u.suspended_until <= NOW())) `
+
+	// 通報を確認中の商品は検索結果に出さない
+	query += ` AND NOT EXISTS (
+	               SELECT 1 FROM reports r
+	               WHERE r.target_type = 'product' AND r.target_id = p.id AND r.status = 'reviewing'
+	           ) `
+
+	if f.TargetUserID != "" {
 		query += " AND p.user_id = ? "
-		args = append(args, targetUserID)
+		args = append(args, f.TargetUserID)
 	}
-	if keyword != "" {
+	if f.FollowedBy != "" {
+		query += " AND p.user_id IN (SELECT followee_id FROM follows WHERE follower_id = ?) "
+		args = append(args, f.FollowedBy)
+	}
+	if f.Keyword != "" {
 		query += " AND p.name LIKE ? "
-		args = append(args, "%"+keyword+"%")
+		args = append(args, "%"+f.Keyword+"%")
+	}
+	// 自然文検索のキーワード・属性 (グループ内は OR、グループ同士は AND)
+	for _, group := range f.TermGroups {
+		if len(group) == 0 {
+			continue
+		}
+		var parts []string
+		for _, t := range group {
+			parts = append(parts, "p.name LIKE ? OR p.description LIKE ?")
+			args = append(args, "%"+t+"%", "%"+t+"%")
+		}
+		query += " AND (" + strings.Join(parts, " OR ") + ") "
+	}
+	if f.MinPrice > 0 {
+		query += " AND p.price >= ? "
+		args = append(args, f.MinPrice)
+	}
+	if f.MaxPrice > 0 {
+		query += " AND p.price <= ? "
+		args = append(args, f.MaxPrice)
 	}
-	if status == "selling" {
+	if f.Status == "selling" {
 		query += " AND p.buyer_id IS NULL "
-	} else if status == "sold" {
+	} else if f.Status == "sold" {
 		query += " AND p.buyer_id IS NOT NULL "
 	}
 
 	return query, args
 }
 
-func (d *ProductDao) Search(keyword, sortOrder, status, currentUserID, targetUserID string, limit, offset int) ([]*model.Product, error) {
-	whereQuery, args := d.buildSearchCondition(keyword, status, targetUserID)
+func (d *ProductDao) Search(filter model.ProductFilter, sortOrder, currentUserID string, limit, offset int) ([]*model.Product, error) {
+	whereQuery, args := d.buildSearchCondition(filter)
 
 	selectQuery := `
 		SELECT 
@@ -68,12 +106,10 @@ func (d *ProductDao) Search(keyword, sortOrder, status, currentUserID, targetUse
 			COALESCE(u.image_url, ''),   
 			COALESCE(u2.name, ''),
 			COALESCE(u2.image_url, ''),
-			(SELECT COUNT(*) FROM likes WHERE product_id = p.id) as like_count,
-			EXISTS(SELECT 1 FROM likes WHERE product_id = p.id AND user_id = ?) as is_liked
+			p.like_count,
+			p.taken_down_at IS NOT NULL as is_taken_down
 	` + whereQuery
 
-	finalArgs := append([]interface{}{currentUserID}, args...)
-
 	switch sortOrder {
 	case "price_asc":
 		selectQuery += " ORDER BY p.price ASC "
@@ -82,19 +118,22 @@ func (d *ProductDao) Search(keyword, sortOrder, status, currentUserID, targetUse
 	case "oldest":
 		selectQuery += " ORDER BY p.created_at ASC "
 	case "likes":
-		selectQuery += " ORDER BY like_count DESC, p.created_at DESC "
+		selectQuery += " ORDER BY p.like_count DESC, p.created_at DESC "
+	case "trending":
+		// 急上昇スコアは TrendingUsecase が定期的に更新する
+		selectQuery += " ORDER BY p.trending_score DESC, p.created_at DESC "
 	default:
 		selectQuery += " ORDER BY p.created_at DESC "
 	}
 
 	selectQuery += " LIMIT ? OFFSET ? "
-	finalArgs = append(finalArgs, limit, offset)
+	args = append(args, limit, offset)
 
-	return d.fetchProducts(selectQuery, finalArgs...)
+	return d.fetchProducts(currentUserID, selectQuery, args...)
 }
 
-func (d *ProductDao) SearchCount(keyword, status, targetUserID string) (int, error) {
-	whereQuery, args := d.buildSearchCondition(keyword, status, targetUserID)
+func (d *ProductDao) SearchCount(filter model.ProductFilter) (int, error) {
+	whereQuery, args := d.buildSearchCondition(filter)
 	query := `SELECT COUNT(*) ` + whereQuery
 
 	var count int
@@ -112,14 +151,14 @@ func (d *ProductDao) FindByID(productID, currentUserID string) (*model.Product,
 			COALESCE(u.image_url, ''),   
 			COALESCE(u2.name, ''),
 			COALESCE(u2.image_url, ''),
-			(SELECT COUNT(*) FROM likes WHERE product_id = p.id) as like_count,
-			EXISTS(SELECT 1 FROM likes WHERE product_id = p.id AND user_id = ?) as is_liked
+			p.like_count,
+			p.taken_down_at IS NOT NULL as is_taken_down
 		FROM products p
 		JOIN users u ON p.user_id = u.id
 		LEFT JOIN users u2 ON p.buyer_id = u2.id -- ★追加
 		WHERE p.id = ?
 	`
-	products, err := d.fetchProducts(query, currentUserID, productID)
+	products, err := d.fetchProducts(currentUserID, query, productID)
 	if err != nil {
 		return nil, err
 	}
@@ -139,15 +178,15 @@ func (d *ProductDao) FindByUserID(targetUserID, currentUserID string) ([]*model.
 			COALESCE(u.image_url, ''),  -- ★追加
 			COALESCE(u2.name, ''),
 			COALESCE(u2.image_url, ''), -- ★追加
-			(SELECT COUNT(*) FROM likes WHERE product_id = p.id) as like_count,
-			EXISTS(SELECT 1 FROM likes WHERE product_id = p.id AND user_id = ?) as is_liked
+			p.like_count,
+			p.taken_down_at IS NOT NULL as is_taken_down
 		FROM products p
 		JOIN users u ON p.user_id = u.id
 		LEFT JOIN users u2 ON p.buyer_id = u2.id
-		WHERE p.user_id = ?
+		WHERE p.user_id = ?` + hideTakenDown(targetUserID, currentUserID) + `
 		ORDER BY p.created_at DESC
 	`
-	return d.fetchProducts(query, currentUserID, targetUserID)
+	return d.fetchProducts(currentUserID, query, targetUserID)
 }
 
 // FindByBuyerID:特定のユーザーが購入した商品
@@ -160,15 +199,15 @@ func (d *ProductDao) FindByBuyerID(targetBuyerID, currentUserID string) ([]*mode
 			COALESCE(u.image_url, ''),  -- ★追加
 			COALESCE(u2.name, ''),
 			COALESCE(u2.image_url, ''), -- ★追加
-			(SELECT COUNT(*) FROM likes WHERE product_id = p.id) as like_count,
-			EXISTS(SELECT 1 FROM likes WHERE product_id = p.id AND user_id = ?) as is_liked
+			p.like_count,
+			p.taken_down_at IS NOT NULL as is_taken_down
 		FROM products p
 		JOIN users u ON p.user_id = u.id
 		LEFT JOIN users u2 ON p.buyer_id = u2.id
-		WHERE p.buyer_id = ?
+		WHERE p.buyer_id = ?` + hideTakenDown(targetBuyerID, currentUserID) + `
 		ORDER BY p.created_at DESC
 	`
-	return d.fetchProducts(query, currentUserID, targetBuyerID)
+	return d.fetchProducts(currentUserID, query, targetBuyerID)
 }
 
 // FindLikedProducts: 特定のユーザーがいいねした商品
@@ -181,20 +220,29 @@ func (d *ProductDao) FindLikedProducts(targetUserID, currentUserID string) ([]*m
 			COALESCE(u.image_url, ''),  -- ★追加
 			COALESCE(u2.name, ''),
 			COALESCE(u2.image_url, ''), -- ★追加
-			(SELECT COUNT(*) FROM likes WHERE product_id = p.id) as like_count,
-			EXISTS(SELECT 1 FROM likes WHERE product_id = p.id AND user_id = ?) as is_liked
+			p.like_count,
+			p.taken_down_at IS NOT NULL as is_taken_down
 		FROM products p
 		JOIN users u ON p.user_id = u.id
 		LEFT JOIN users u2 ON p.buyer_id = u2.id
 		JOIN likes l ON p.id = l.product_id
-		WHERE l.user_id = ?
+		WHERE l.user_id = ?` + hideTakenDown(targetUserID, currentUserID) + `
 		ORDER BY p.created_at DESC
 	`
-	return d.fetchProducts(query, currentUserID, targetUserID)
+	return d.fetchProducts(currentUserID, query, targetUserID)
+}
+
+// 非表示にされた商品は本人の一覧にだけ出す (ほかの人が見る一覧では除く)
+func hideTakenDown(targetUserID, currentUserID string) string {
+	if currentUserID != "" && targetUserID == currentUserID {
+		return ""
+	}
+	return " AND p.taken_down_at IS NULL"
 }
 
 // 共通処理
-func (d *ProductDao) fetchProducts(query string, args ...interface{}) ([]*model.Product, error) {
+// currentUserID がログイン中のユーザーなら、いいね済みかどうかをまとめて調べて IsLiked に入れる
+func (d *ProductDao) fetchProducts(currentUserID string, query string, args ...interface{}) ([]*model.Product, error) {
 	rows, err := d.db.Query(query, args...)
 	if err != nil {
 		return nil, err
@@ -214,7 +262,8 @@ func (d *ProductDao) fetchProducts(query string, args ...interface{}) ([]*model.
 			&p.UserImageURL,
 			&p.BuyerName,
 			&p.BuyerImageURL,
-			&p.LikeCount, &p.IsLiked,
+			&p.LikeCount,
+			&p.IsTakenDown,
 		)
 		if err != nil {
 			return nil, err
@@ -224,18 +273,68 @@ func (d *ProductDao) fetchProducts(query string, args ...interface{}) ([]*model.
 		}
 		products = append(products, p)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if currentUserID != "" && len(products) > 0 {
+		ids := make([]string, len(products))
+		for i, p := range products {
+			ids[i] = p.ID
+		}
+		liked, err := d.likedProductIDs(currentUserID, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range products {
+			p.IsLiked = liked[p.ID]
+		}
+	}
 	return products, nil
 }
 
+// likedProductIDs: ids のうちユーザーがいいねしている商品 (一覧の1ページ分を1回のクエリで調べる)
+func (d *ProductDao) likedProductIDs(userID string, ids []string) (map[string]bool, error) {
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	query := `SELECT product_id FROM likes WHERE user_id = ? AND product_id IN (` + placeholders + `)`
+	args := []interface{}{userID}
+	for _, id := range ids {
+		args = append(args, id)
+	}
+
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	liked := make(map[string]bool)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		liked[id] = true
+	}
+	return liked, rows.Err()
+}
+
 // UpdateBuyerID は購入処理です（既に売れていないかチェックも含みます）
-func (d *ProductDao) UpdateBuyerID(productID string, buyerID string) error {
+// UpdateBuyerID: 購入者を設定し、届け先を写しておく (同じトランザクションで行う)
+func (d *ProductDao) UpdateBuyerID(productID string, buyerID string, shipping *model.AddressFields) error {
+	tx, err := d.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	// buyer_id が NULL の場合のみ更新する（＝早い者勝ち）
 	query := `
 		UPDATE products 
-		SET buyer_id = ? 
-		WHERE id = ? AND buyer_id IS NULL
+		SET buyer_id = ?, sold_at = NOW()
+		WHERE id = ? AND buyer_id IS NULL AND taken_down_at IS NULL
 	`
-	result, err := d.db.Exec(query, buyerID, productID)
+	result, err := tx.Exec(query, buyerID, productID)
 	if err != nil {
 		return err
 	}
@@ -249,7 +348,16 @@ func (d *ProductDao) UpdateBuyerID(productID string, buyerID string) error {
 		return fmt.Errorf("sold out or not found")
 	}
 
-	return nil
+	// 取引のキャンセル後に買い直された場合に備えて上書きする
+	_, err = tx.Exec(`
+		REPLACE INTO sale_shipping_addresses (product_id, buyer_id, name, postal_code, prefecture, city, line1, line2, phone)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, productID, buyerID, shipping.Name, shipping.PostalCode, shipping.Prefecture, shipping.City, shipping.Line1, shipping.Line2, shipping.Phone)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
 func (d *ProductDao) Delete(productID string, userID string) error {
@@ -309,3 +417,190 @@ func (d *ProductDao) Update(productID string, userID string, name string, price
 
 	return nil
 }
+
+// TakeDown: 運営による出品の取り下げ (出品者に関係なく実行できる)
+func (d *ProductDao) TakeDown(tx *sql.Tx, productID string) error {
+	query := `UPDATE products SET taken_down_at = NOW() WHERE id = ? AND taken_down_at IS NULL`
+	result, err := tx.Exec(query, productID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows // 存在しない or 取り下げ済み
+	}
+	return nil
+}
+
+// Restore: 取り下げを解除
+func (d *ProductDao) Restore(tx *sql.Tx, productID string) error {
+	query := `UPDATE products SET taken_down_at = NULL WHERE id = ? AND taken_down_at IS NOT NULL`
+	result, err := tx.Exec(query, productID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows // 存在しない or 取り下げられていない
+	}
+	return nil
+}
+
+// CancelPurchase: 運営による取引の強制キャンセル (購入者を外して出品中に戻し、届け先の写しも消す)
+func (d *ProductDao) CancelPurchase(tx *sql.Tx, productID string) error {
+	query := `UPDATE products SET buyer_id = NULL, sold_at = NULL WHERE id = ? AND buyer_id IS NOT NULL`
+	result, err := tx.Exec(query, productID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows // 存在しない or 取引中でない
+	}
+	_, err = tx.Exec("DELETE FROM sale_shipping_addresses WHERE product_id = ?", productID)
+	return err
+}
+
+// FindSoldComparables: キーワードに一致する売れた商品を、一致数の多い順に取得
+// 1語だけ一致した商品 (「ケース」など汎用的な語だけ) が混ざらないよう、キーワードの2/3以上 (切り上げ) に一致するものだけを返します
+func (d *ProductDao) FindSoldComparables(keywords []string, limit int) ([]*model.ComparableProduct, error) {
+	if len(keywords) == 0 {
+		return nil, nil
+	}
+
+	// 一致したキーワードの数をスコアにする (商品名での一致は2点、説明文は1点)
+	var scoreParts, matchParts []string
+	var scoreArgs, matchArgs []interface{}
+	for _, k := range keywords {
+		like := "%" + escapeLike(k) + "%"
+		scoreParts = append(scoreParts, `(CASE WHEN p.name LIKE ? ESCAPE '\\' THEN 2 WHEN p.description LIKE ? ESCAPE '\\' THEN 1 ELSE 0 END)`)
+		scoreArgs = append(scoreArgs, like, like)
+		matchParts = append(matchParts, `(p.name LIKE ? ESCAPE '\\' OR p.description LIKE ? ESCAPE '\\')`)
+		matchArgs = append(matchArgs, like, like)
+	}
+	minMatches := (2*len(keywords) + 2) / 3
+
+	query := `
+		SELECT p.id, p.name, p.price, p.created_at, ` + strings.Join(scoreParts, " + ") + ` AS score
+		FROM products p
+		WHERE p.buyer_id IS NOT NULL
+		  AND p.taken_down_at IS NULL
+		  AND ` + strings.Join(matchParts, " + ") + ` >= ?
+		ORDER BY score DESC, p.created_at DESC
+		LIMIT ?
+	`
+	args := append(scoreArgs, matchArgs...)
+	args = append(args, minMatches, limit)
+
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var products []*model.ComparableProduct
+	for rows.Next() {
+		p := &model.ComparableProduct{}
+		var score int
+		if err := rows.Scan(&p.ID, &p.Name, &p.Price, &p.CreatedAt, &score); err != nil {
+			return nil, err
+		}
+		products = append(products, p)
+	}
+	return products, nil
+}
+
+// FindSellingByIDs: 出品中で検索結果に出せる商品をIDで取得 (順番は ids の順に並べ直す)
+func (d *ProductDao) FindSellingByIDs(ids []string, currentUserID string) ([]*model.Product, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	whereQuery, args := d.buildSearchCondition(model.ProductFilter{Status: "selling"})
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(ids)), ",")
+	query := `
+		SELECT 
+			p.id, p.name, p.price, p.description, p.user_id,
+			COALESCE(p.image_url, ''), p.created_at, p.buyer_id, 
+			u.name, 
+			COALESCE(u.image_url, ''),   
+			COALESCE(u2.name, ''),
+			COALESCE(u2.image_url, ''),
+			p.like_count,
+			p.taken_down_at IS NOT NULL as is_taken_down
+	` + whereQuery + ` AND p.id IN (` + placeholders + `)`
+
+	for _, id := range ids {
+		args = append(args, id)
+	}
+
+	products, err := d.fetchProducts(currentUserID, query, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*model.Product, len(products))
+	for _, p := range products {
+		byID[p.ID] = p
+	}
+	sorted := make([]*model.Product, 0, len(products))
+	for _, id := range ids {
+		if p, ok := byID[id]; ok {
+			sorted = append(sorted, p)
+		}
+	}
+	return sorted, nil
+}
+
+// FindSellingByKeywords: キーワードに一致する出品中の商品を、一致数の多い順に取得 (excludeID は除く)
+func (d *ProductDao) FindSellingByKeywords(keywords []string, excludeID, currentUserID string, limit int) ([]*model.Product, error) {
+	if len(keywords) == 0 {
+		return nil, nil
+	}
+
+	whereQuery, args := d.buildSearchCondition(model.ProductFilter{Status: "selling"})
+
+	// スコアの付け方は FindSoldComparables と同じ (商品名での一致は2点、説明文は1点)
+	var scoreParts, whereParts []string
+	var scoreArgs, whereArgs []interface{}
+	for _, k := range keywords {
+		like := "%" + k + "%"
+		scoreParts = append(scoreParts, "(CASE WHEN p.name LIKE ? THEN 2 WHEN p.description LIKE ? THEN 1 ELSE 0 END)")
+		scoreArgs = append(scoreArgs, like, like)
+		whereParts = append(whereParts, "p.name LIKE ? OR p.description LIKE ?")
+		whereArgs = append(whereArgs, like, like)
+	}
+
+	query := `
+		SELECT 
+			p.id, p.name, p.price, p.description, p.user_id,
+			COALESCE(p.image_url, ''), p.created_at, p.buyer_id, 
+			u.name, 
+			COALESCE(u.image_url, ''),   
+			COALESCE(u2.name, ''),
+			COALESCE(u2.image_url, ''),
+			p.like_count,
+			p.taken_down_at IS NOT NULL as is_taken_down
+	` + whereQuery + `
+		  AND p.id <> ?
+		  AND (` + strings.Join(whereParts, " OR ") + `)
+		ORDER BY ` + strings.Join(scoreParts, " + ") + ` DESC, p.created_at DESC
+		LIMIT ?
+	`
+
+	args = append(args, excludeID)
+	args = append(args, whereArgs...)
+	args = append(args, scoreArgs...)
+	args = append(args, limit)
+
+	return d.fetchProducts(currentUserID, query, args...)
+}
@@ -0,0 +1,117 @@
+package dao
+
+import (
+	"database/sql"
+	"encoding/binary"
+	"fmt"
+	"hackathon-backend/model"
+	"math"
+)
+
+type ProductEmbeddingDao struct {
+	db *sql.DB
+}
+
+func NewProductEmbeddingDao(db *sql.DB) *ProductEmbeddingDao {
+	return &ProductEmbeddingDao{db: db}
+}
+
+// Upsert: 商品のベクトルを保存 (既にあれば置き換える)
+func (d *ProductEmbeddingDao) Upsert(e *model.ProductEmbedding) error {
+	query := `
+		INSERT INTO product_embeddings (product_id, model, dims, vector)
+		VALUES (?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE model = VALUES(model), dims = VALUES(dims), vector = VALUES(vector)
+	`
+	_, err := d.db.Exec(query, e.ProductID, e.Model, len(e.Vector), encodeVector(e.Vector))
+	return err
+}
+
+// FindByProductID: 商品のベクトルを取得 (なければ nil)
+func (d *ProductEmbeddingDao) FindByProductID(productID string) (*model.ProductEmbedding, error) {
+	query := `SELECT product_id, model, dims, vector, updated_at FROM product_embeddings WHERE product_id = ?`
+	e, err := scanEmbedding(d.db.QueryRow(query, productID))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return e, err
+}
+
+// ListByModel: 指定したモデルで作られたベクトルを全件取得 (インデックスの構築用)
+func (d *ProductEmbeddingDao) ListByModel(modelName string) ([]*model.ProductEmbedding, error) {
+	query := `SELECT product_id, model, dims, vector, updated_at FROM product_embeddings WHERE model = ?`
+	rows, err := d.db.Query(query, modelName)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var embeddings []*model.ProductEmbedding
+	for rows.Next() {
+		e, err := scanEmbedding(rows)
+		if err != nil {
+			return nil, err
+		}
+		embeddings = append(embeddings, e)
+	}
+	return embeddings, rows.Err()
+}
+
+// ListMissing: modelName の埋め込みがまだない出品中の商品 (新しい順、最大 limit 件)
+// 返す商品には ID・出品者・商品名・説明文だけが入っています
+func (d *ProductEmbeddingDao) ListMissing(modelName string, limit int) ([]*model.Product, error) {
+	query := `
+		SELECT p.id, p.user_id, p.name, p.description
+		FROM products p
+		LEFT JOIN product_embeddings e ON e.product_id = p.id AND e.model = ?
+		WHERE e.product_id IS NULL AND p.buyer_id IS NULL AND p.taken_down_at IS NULL
+		ORDER BY p.created_at DESC
+		LIMIT ?
+	`
+	rows, err := d.db.Query(query, modelName, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var products []*model.Product
+	for rows.Next() {
+		p := &model.Product{}
+		if err := rows.Scan(&p.ID, &p.UserID, &p.Name, &p.Description); err != nil {
+			return nil, err
+		}
+		products = append(products, p)
+	}
+	return products, rows.Err()
+}
+
+func scanEmbedding(row interface{ Scan(...interface{}) error }) (*model.ProductEmbedding, error) {
+	e := &model.ProductEmbedding{}
+	var dims int
+	var blob []byte
+	if err := row.Scan(&e.ProductID, &e.Model, &dims, &blob, &e.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if len(blob) != dims*4 {
+		return nil, fmt.Errorf("embedding of %s has %d bytes, want %d", e.ProductID, len(blob), dims*4)
+	}
+	e.Vector = decodeVector(blob)
+	return e, nil
+}
+
+// float32 をリトルエンディアンで並べたバイト列にする
+func encodeVector(v []float32) []byte {
+	b := make([]byte, len(v)*4)
+	for i, f := range v {
+		binary.LittleEndian.PutUint32(b[i*4:], math.Float32bits(f))
+	}
+	return b
+}
+
+func decodeVector(b []byte) []float32 {
+	v := make([]float32, len(b)/4)
+	for i := range v {
+		v[i] = math.Float32frombits(binary.LittleEndian.Uint32(b[i*4:]))
+	}
+	return v
+}
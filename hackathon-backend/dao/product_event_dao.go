@@ -0,0 +1,78 @@
+package dao
+
+import (
+	"database/sql"
+	"time"
+)
+
+type ProductEventDao struct {
+	db *sql.DB
+}
+
+func NewProductEventDao(db *sql.DB) *ProductEventDao {
+	return &ProductEventDao{db: db}
+}
+
+// CreateView: 閲覧を記録 (同じ閲覧者の同じ日の閲覧は1回だけ。未ログインは userID を空文字で渡す)
+// 記録したら true、その日に記録済みなら false を返します
+func (d *ProductEventDao) CreateView(productID, userID, viewerKey string, date time.Time) (bool, error) {
+	query := `
+		INSERT IGNORE INTO product_events (product_id, user_id, event_type, viewer_key, view_date)
+		VALUES (?, NULLIF(?, ''), 'view', ?, ?)
+	`
+	result, err := d.db.Exec(query, productID, userID, viewerKey, date.Format("2006-01-02"))
+	if err != nil {
+		return false, err
+	}
+	n, err := result.RowsAffected()
+	return n > 0, err
+}
+
+// Create: 閲覧以外の反応 (いいね) を記録
+func (d *ProductEventDao) Create(productID, userID, eventType string) error {
+	query := "INSERT INTO product_events (product_id, user_id, event_type) VALUES (?, NULLIF(?, ''), ?)"
+	_, err := d.db.Exec(query, productID, userID, eventType)
+	return err
+}
+
+// Delete: ユーザーの記録を取り消す (いいね解除時。付け外しでスコアを稼げないようにする)
+func (d *ProductEventDao) Delete(productID, userID, eventType string) error {
+	query := "DELETE FROM product_events WHERE product_id = ? AND user_id = ? AND event_type = ?"
+	_, err := d.db.Exec(query, productID, userID, eventType)
+	return err
+}
+
+// RecomputeTrendingScores: window 以内の閲覧・いいねに、halfLife ごとに半分になる重みを付けて合計し、
+// products.trending_score に保存します。影響した行数を返します
+func (d *ProductEventDao) RecomputeTrendingScores(window, halfLife time.Duration, viewWeight, likeWeight float64) (int64, error) {
+	query := `
+		UPDATE products p
+		LEFT JOIN (
+			SELECT product_id,
+			       SUM(CASE event_type WHEN 'like' THEN ? ELSE ? END
+			           * EXP(-LN(2) * TIMESTAMPDIFF(SECOND, created_at, NOW()) / ?)) AS score
+			FROM product_events
+			WHERE created_at >= ?
+			GROUP BY product_id
+		) s ON s.product_id = p.id
+		SET p.trending_score = COALESCE(s.score, 0)
+		WHERE p.trending_score <> COALESCE(s.score, 0)
+	`
+	since := time.Now().Add(-window)
+	result, err := d.db.Exec(query, likeWeight, viewWeight, halfLife.Seconds(), since)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+// DeleteOlderThan: age より前の記録を最大 limit 件削除し、削除した件数を返します
+// 一度に大量に消してテーブルを長くロックしないよう、呼び出し側で件数が limit 未満になるまで繰り返してください
+func (d *ProductEventDao) DeleteOlderThan(age time.Duration, limit int) (int64, error) {
+	query := `DELETE FROM product_events WHERE created_at < NOW() - INTERVAL ? SECOND LIMIT ?`
+	result, err := d.db.Exec(query, int64(age/time.Second), limit)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
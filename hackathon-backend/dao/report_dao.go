@@ -0,0 +1,118 @@
+package dao
+
+import (
+	"database/sql"
+	"hackathon-backend/model"
+)
+
+type ReportDao struct {
+	db *sql.DB
+}
+
+func NewReportDao(db *sql.DB) *ReportDao {
+	return &ReportDao{db: db}
+}
+
+// Create: 通報を保存
+func (d *ReportDao) Create(r *model.Report) error {
+	query := `
+		INSERT INTO reports (id, reporter_id, target_type, target_id, reason, note, status, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := d.db.Exec(query, r.ID, r.ReporterID, r.TargetType, r.TargetID, r.Reason, r.Note, r.Status, r.CreatedAt, r.UpdatedAt)
+	return err
+}
+
+// FindByID: 通報を1件取得 (見つからなければ nil, nil)
+func (d *ReportDao) FindByID(id string) (*model.Report, error) {
+	query := `
+		SELECT id, reporter_id, target_type, target_id, reason, COALESCE(note, ''), status,
+		       COALESCE(moderator_id, ''), COALESCE(resolution, ''), created_at, updated_at
+		FROM reports
+		WHERE id = ?
+	`
+	reports, err := d.fetchReports(query, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(reports) == 0 {
+		return nil, nil
+	}
+	return reports[0], nil
+}
+
+// HasUnresolved: 同じ人が同じ対象に未解決の通報をしているか確認
+func (d *ReportDao) HasUnresolved(reporterID, targetType, targetID string) (bool, error) {
+	query := `
+		SELECT COUNT(*) FROM reports
+		WHERE reporter_id = ? AND target_type = ? AND target_id = ? AND status IN ('open', 'reviewing')
+	`
+	var count int
+	if err := d.db.QueryRow(query, reporterID, targetType, targetID).Scan(&count); err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// 一覧用の検索条件を作成するヘルパー
+func (d *ReportDao) buildListCondition(status, targetType string) (string, []interface{}) {
+	query := ` FROM reports WHERE 1=1 `
+	var args []interface{}
+
+	if status != "" {
+		query += " AND status = ? "
+		args = append(args, status)
+	}
+	if targetType != "" {
+		query += " AND target_type = ? "
+		args = append(args, targetType)
+	}
+	return query, args
+}
+
+// List: モデレーター用の通報一覧 (古い順 = 対応待ちが長いものから)
+func (d *ReportDao) List(status, targetType string, limit, offset int) ([]*model.Report, error) {
+	whereQuery, args := d.buildListCondition(status, targetType)
+	query := `
+		SELECT id, reporter_id, target_type, target_id, reason, COALESCE(note, ''), status,
+		       COALESCE(moderator_id, ''), COALESCE(resolution, ''), created_at, updated_at
+	` + whereQuery + ` ORDER BY created_at ASC LIMIT ? OFFSET ? `
+	args = append(args, limit, offset)
+	return d.fetchReports(query, args...)
+}
+
+func (d *ReportDao) Count(status, targetType string) (int, error) {
+	whereQuery, args := d.buildListCondition(status, targetType)
+	var count int
+	err := d.db.QueryRow(`SELECT COUNT(*) `+whereQuery, args...).Scan(&count)
+	return count, err
+}
+
+// UpdateStatus: 対応状況を更新
+func (d *ReportDao) UpdateStatus(tx *sql.Tx, id, status, moderatorID, resolution string) error {
+	query := `UPDATE reports SET status = ?, moderator_id = ?, resolution = ? WHERE id = ?`
+	_, err := tx.Exec(query, status, moderatorID, resolution, id)
+	return err
+}
+
+// 共通処理
+func (d *ReportDao) fetchReports(query string, args ...interface{}) ([]*model.Report, error) {
+	rows, err := d.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reports []*model.Report
+	for rows.Next() {
+		r := &model.Report{}
+		if err := rows.Scan(
+			&r.ID, &r.ReporterID, &r.TargetType, &r.TargetID, &r.Reason, &r.Note, &r.Status,
+			&r.ModeratorID, &r.Resolution, &r.CreatedAt, &r.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		reports = append(reports, r)
+	}
+	return reports, nil
+}
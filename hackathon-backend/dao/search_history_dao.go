@@ -0,0 +1,45 @@
+package dao
+
+import (
+	"database/sql"
+)
+
+type SearchHistoryDao struct {
+	db *sql.DB
+}
+
+func NewSearchHistoryDao(db *sql.DB) *SearchHistoryDao {
+	return &SearchHistoryDao{db: db}
+}
+
+// Create: 検索ワードを記録
+func (d *SearchHistoryDao) Create(userID, query string) error {
+	_, err := d.db.Exec("INSERT INTO search_history (user_id, query) VALUES (?, ?)", userID, query)
+	return err
+}
+
+// FindRecent: 最近の検索ワードを新しい順に重複なしで取得
+func (d *SearchHistoryDao) FindRecent(userID string, limit int) ([]string, error) {
+	query := `
+		SELECT query FROM search_history
+		WHERE user_id = ?
+		GROUP BY query
+		ORDER BY MAX(created_at) DESC
+		LIMIT ?
+	`
+	rows, err := d.db.Query(query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var queries []string
+	for rows.Next() {
+		var q string
+		if err := rows.Scan(&q); err != nil {
+			return nil, err
+		}
+		queries = append(queries, q)
+	}
+	return queries, nil
+}
@@ -0,0 +1,83 @@
+package dao
+
+import (
+	"database/sql"
+	"hackathon-backend/model"
+	"time"
+)
+
+type SellerAnalyticsDao struct {
+	db *sql.DB
+}
+
+func NewSellerAnalyticsDao(db *sql.DB) *SellerAnalyticsDao {
+	return &SellerAnalyticsDao{db: db}
+}
+
+// ListingStats: 出品者の全商品について、期間内の閲覧・いいね・メッセージの相手の数を集計 (新しい順)
+func (d *SellerAnalyticsDao) ListingStats(sellerID string, from, to time.Time) ([]*model.ListingAnalytics, error) {
+	query := `
+		SELECT p.id, p.name, p.price, p.created_at, p.buyer_id IS NOT NULL, p.sold_at,
+			(SELECT COUNT(*) FROM product_events e
+			 WHERE e.product_id = p.id AND e.event_type = 'view' AND e.created_at >= ? AND e.created_at < ?),
+			(SELECT COUNT(*) FROM product_events e
+			 WHERE e.product_id = p.id AND e.event_type = 'like' AND e.created_at >= ? AND e.created_at < ?),
+			(SELECT COUNT(DISTINCT CASE WHEN m.sender_id = p.user_id THEN m.receiver_id ELSE m.sender_id END)
+			 FROM messages m
+			 WHERE m.product_id = p.id AND m.created_at >= ? AND m.created_at < ?)
+		FROM products p
+		WHERE p.user_id = ?
+		ORDER BY p.created_at DESC
+	`
+	rows, err := d.db.Query(query, from, to, from, to, from, to, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var listings []*model.ListingAnalytics
+	for rows.Next() {
+		l := &model.ListingAnalytics{}
+		var soldAt sql.NullTime
+		if err := rows.Scan(&l.ProductID, &l.Name, &l.Price, &l.CreatedAt, &l.IsSold, &soldAt, &l.Views, &l.Likes, &l.MessageThreads); err != nil {
+			return nil, err
+		}
+		if soldAt.Valid {
+			l.SoldAt = &soldAt.Time
+		}
+		listings = append(listings, l)
+	}
+	return listings, nil
+}
+
+// DailyEvents: 出品者の商品への閲覧・いいねを日ごとに集計 (キーは日付 → 種類)
+func (d *SellerAnalyticsDao) DailyEvents(sellerID string, from, to time.Time) (map[string]map[string]int, error) {
+	query := `
+		SELECT DATE(e.created_at), e.event_type, COUNT(*)
+		FROM product_events e
+		JOIN products p ON e.product_id = p.id
+		WHERE p.user_id = ? AND e.created_at >= ? AND e.created_at < ?
+		GROUP BY DATE(e.created_at), e.event_type
+	`
+	rows, err := d.db.Query(query, sellerID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := make(map[string]map[string]int)
+	for rows.Next() {
+		var day time.Time
+		var eventType string
+		var count int
+		if err := rows.Scan(&day, &eventType, &count); err != nil {
+			return nil, err
+		}
+		key := day.Format(time.DateOnly)
+		if result[key] == nil {
+			result[key] = make(map[string]int)
+		}
+		result[key][eventType] = count
+	}
+	return result, nil
+}
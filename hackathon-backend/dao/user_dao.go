@@ -2,9 +2,14 @@ package dao
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"hackathon-backend/model"
+
+	"github.com/go-sql-driver/mysql"
 )
 
 type UserDao struct {
@@ -18,9 +23,9 @@ func NewUserDao(db *sql.DB) *UserDao {
 func (dao *UserDao) FindByFirebaseUID(firebaseUID string) (*model.User, error) {
 	var user model.User
 	// 1件だけ取得するので QueryRow を使います
-	row := dao.db.QueryRow("SELECT id, name, firebase_uid, COALESCE(bio, ''), COALESCE(image_url, '') FROM users WHERE firebase_uid = ?", firebaseUID)
+	row := dao.db.QueryRow("SELECT id, name, COALESCE(handle, ''), firebase_uid, COALESCE(bio, ''), COALESCE(image_url, ''), role, status, COALESCE(status_reason, ''), suspended_until, COALESCE(preferred_language, '') FROM users WHERE firebase_uid = ?", firebaseUID)
 
-	if err := row.Scan(&user.ID, &user.Name, &user.FirebaseUID, &user.Bio, &user.ImageURL); err != nil {
+	if err := row.Scan(&user.ID, &user.Name, &user.Handle, &user.FirebaseUID, &user.Bio, &user.ImageURL, &user.Role, &user.Status, &user.StatusReason, &user.SuspendedUntil, &user.PreferredLanguage); err != nil {
 		if err == sql.ErrNoRows {
 			// ユーザーが見つからない場合は nil, nil を返す設計にします
 			// (呼び出し元の Usecase や Controller で 404 エラーにするため)
@@ -35,8 +40,21 @@ func (dao *UserDao) FindByFirebaseUID(firebaseUID string) (*model.User, error) {
 
 func (dao *UserDao) FindByID(id string) (*model.User, error) {
 	var user model.User
-	row := dao.db.QueryRow("SELECT id, name, firebase_uid, COALESCE(bio, ''), COALESCE(image_url, '') FROM users WHERE id = ?", id)
-	if err := row.Scan(&user.ID, &user.Name, &user.FirebaseUID, &user.Bio, &user.ImageURL); err != nil {
+	row := dao.db.QueryRow("SELECT id, name, COALESCE(handle, ''), firebase_uid, COALESCE(bio, ''), COALESCE(image_url, ''), role, status, COALESCE(status_reason, ''), suspended_until, COALESCE(preferred_language, '') FROM users WHERE id = ?", id)
+	if err := row.Scan(&user.ID, &user.Name, &user.Handle, &user.FirebaseUID, &user.Bio, &user.ImageURL, &user.Role, &user.Status, &user.StatusReason, &user.SuspendedUntil, &user.PreferredLanguage); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// FindByHandle: ハンドル (正規化済み) からユーザーを取得 (見つからなければ nil, nil)
+func (dao *UserDao) FindByHandle(handle string) (*model.User, error) {
+	var user model.User
+	row := dao.db.QueryRow("SELECT id, name, COALESCE(handle, ''), firebase_uid, COALESCE(bio, ''), COALESCE(image_url, ''), role, status, COALESCE(status_reason, ''), suspended_until, COALESCE(preferred_language, '') FROM users WHERE handle = ?", handle)
+	if err := row.Scan(&user.ID, &user.Name, &user.Handle, &user.FirebaseUID, &user.Bio, &user.ImageURL, &user.Role, &user.Status, &user.StatusReason, &user.SuspendedUntil, &user.PreferredLanguage); err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
@@ -65,8 +83,8 @@ func (dao *UserDao) CreateOrUpdate(ulid string, firebaseUID string, name string)
 
 	// 確定したユーザー情報を取得して返す
 	var user model.User
-	err = tx.QueryRow("SELECT id, name, firebase_uid, COALESCE(bio, ''), COALESCE(image_url, '') FROM users WHERE firebase_uid = ?", firebaseUID).
-		Scan(&user.ID, &user.Name, &user.FirebaseUID, &user.Bio, &user.ImageURL)
+	err = tx.QueryRow("SELECT id, name, COALESCE(handle, ''), firebase_uid, COALESCE(bio, ''), COALESCE(image_url, ''), role, status, COALESCE(status_reason, ''), suspended_until, COALESCE(preferred_language, '') FROM users WHERE firebase_uid = ?", firebaseUID).
+		Scan(&user.ID, &user.Name, &user.Handle, &user.FirebaseUID, &user.Bio, &user.ImageURL, &user.Role, &user.Status, &user.StatusReason, &user.SuspendedUntil, &user.PreferredLanguage)
 	if err != nil {
 		return nil, fmt.Errorf("fail: tx.QueryRow, %v", err)
 	}
@@ -78,8 +96,79 @@ func (dao *UserDao) CreateOrUpdate(ulid string, firebaseUID string, name string)
 	return &user, nil
 }
 
-func (dao *UserDao) Update(user *model.User) error {
-	query := `UPDATE users SET name = ?, bio = ?, image_url = ? WHERE id = ?`
-	_, err := dao.db.Exec(query, user.Name, user.Bio, user.ImageURL, user.ID)
+// Update: プロフィール (名前・自己紹介・画像・言語・ハンドル) を1つの UPDATE で保存する
+// ハンドルが他のユーザーに使われていたら何も変えずに false を返します
+func (dao *UserDao) Update(user *model.User) (bool, error) {
+	query := `UPDATE users SET name = ?, bio = ?, image_url = ?, preferred_language = NULLIF(?, ''), handle = NULLIF(?, '') WHERE id = ?`
+	_, err := dao.db.Exec(query, user.Name, user.Bio, user.ImageURL, user.PreferredLanguage, user.Handle, user.ID)
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// UpdateRole: ロールを変更
+func (dao *UserDao) UpdateRole(tx *sql.Tx, id, role string) error {
+	_, err := tx.Exec(`UPDATE users SET role = ? WHERE id = ?`, role, id)
 	return err
 }
+
+// UpdateStatus: アカウント状態を変更 (active に戻すときは reason, until は空で渡す)
+func (dao *UserDao) UpdateStatus(tx *sql.Tx, id, status, reason string, until *time.Time) error {
+	query := `UPDATE users SET status = ?, status_reason = NULLIF(?, ''), suspended_until = ? WHERE id = ?`
+	_, err := tx.Exec(query, status, reason, until, id)
+	return err
+}
+
+// Search: 名前の部分一致またはハンドルの前方一致でユーザーを探す (BANされたユーザー・退会したユーザーは除く)
+// ハンドルが完全に一致する人を先頭にします
+func (dao *UserDao) Search(q string, limit, offset int) ([]*model.UserSummary, error) {
+	query := `
+		SELECT id, name, COALESCE(handle, ''), COALESCE(image_url, ''), COALESCE(bio, '')
+		FROM users
+		WHERE ` + userSearchCondition + `
+		ORDER BY handle = ? DESC, name ASC, id ASC
+		LIMIT ? OFFSET ?
+	`
+	handle := model.NormalizeHandle(q)
+	rows, err := dao.db.Query(query, model.UserStatusBanned, model.UserStatusDeleted, "%"+escapeLike(q)+"%", escapeLike(handle)+"%", handle, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []*model.UserSummary{}
+	for rows.Next() {
+		u := &model.UserSummary{}
+		if err := rows.Scan(&u.ID, &u.Name, &u.Handle, &u.ImageURL, &u.Bio); err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	return users, rows.Err()
+}
+
+// SearchCount: Search に一致するユーザーの数
+func (dao *UserDao) SearchCount(q string) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM users WHERE ` + userSearchCondition
+	err := dao.db.QueryRow(query, model.UserStatusBanned, model.UserStatusDeleted, "%"+escapeLike(q)+"%", escapeLike(model.NormalizeHandle(q))+"%").Scan(&count)
+	return count, err
+}
+
+const userSearchCondition = `status NOT IN (?, ?) AND (name LIKE ? ESCAPE '\\' OR handle LIKE ? ESCAPE '\\')`
+
+// escapeLike: LIKE のパターンで特別な意味を持つ文字 (\ % _) をそのままの文字として扱うようにする
+// (ハンドルには _ が使えるので、エスケープしないと任意の1文字として一致してしまう)
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// 一意キーの重複 (ER_DUP_ENTRY)
+const mysqlErrDuplicateEntry = 1062
@@ -0,0 +1,79 @@
+package dao
+
+import (
+	"database/sql"
+	"hackathon-backend/model"
+	"slices"
+	"time"
+)
+
+type UserStatsDao struct {
+	db *sql.DB
+}
+
+func NewUserStatsDao(db *sql.DB) *UserStatsDao {
+	return &UserStatsDao{db: db}
+}
+
+// ListingCounts: 出品数 (削除された商品を除く)・売れた数・買った数
+func (d *UserStatsDao) ListingCounts(userID string) (listed, sold, bought int, err error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM products WHERE user_id = ? AND taken_down_at IS NULL),
+			(SELECT COUNT(*) FROM products WHERE user_id = ? AND buyer_id IS NOT NULL),
+			(SELECT COUNT(*) FROM products WHERE buyer_id = ?)
+	`
+	err = d.db.QueryRow(query, userID, userID, userID).Scan(&listed, &sold, &bought)
+	return listed, sold, bought, err
+}
+
+// LastActiveAt: 出品・メッセージ送信・閲覧・いいねのうち最後の時刻 (何もしていなければ nil)
+func (d *UserStatsDao) LastActiveAt(userID string) (*time.Time, error) {
+	query := `
+		SELECT MAX(t) FROM (
+			SELECT MAX(created_at) AS t FROM products WHERE user_id = ?
+			UNION ALL
+			SELECT MAX(created_at) FROM messages WHERE sender_id = ?
+			UNION ALL
+			SELECT MAX(created_at) FROM product_events WHERE user_id = ?
+		) AS activity
+	`
+	var t sql.NullTime
+	if err := d.db.QueryRow(query, userID, userID, userID).Scan(&t); err != nil {
+		return nil, err
+	}
+	if !t.Valid {
+		return nil, nil
+	}
+	return &t.Time, nil
+}
+
+// MessageTimings: since 以降に userID が送った・受け取ったメッセージ (新しいものから最大 limit 件を古い順で)
+func (d *UserStatsDao) MessageTimings(userID string, since time.Time, limit int) ([]*model.MessageTiming, error) {
+	query := `
+		SELECT sender_id, receiver_id, created_at
+		FROM messages
+		WHERE (sender_id = ? OR receiver_id = ?) AND created_at >= ?
+		ORDER BY created_at DESC
+		LIMIT ?
+	`
+	rows, err := d.db.Query(query, userID, userID, since, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var timings []*model.MessageTiming
+	for rows.Next() {
+		m := &model.MessageTiming{}
+		if err := rows.Scan(&m.SenderID, &m.ReceiverID, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		timings = append(timings, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	slices.Reverse(timings)
+	return timings, nil
+}
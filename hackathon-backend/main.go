@@ -8,7 +8,9 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
+	"time"
 
 	"cloud.google.com/go/storage"
 	firebase "firebase.google.com/go"
@@ -45,34 +47,66 @@ func main() {
 	bucketName := os.Getenv("GCS_BUCKET_NAME")
 	storageService := service.NewStorageService(gcsClient, bucketName)
 
-	projectID := "term8-taichi-onishi"
-	location := "asia-northeast1"
-	modelName := "gemini-2.5-flash"
-	geminiService, err := service.NewGeminiService(ctx, projectID, location, modelName)
-	if err != nil {
-		log.Fatalf("failed to init gemini: %v", err)
+	// --- LLM初期化 (LLM_PROVIDER で切り替え) ---
+	llmService := initLLM(ctx)
+	defer llmService.Close()
+	generationCache := service.NewMemoryCache(5000) // AI生成結果・プロフィールの実績のキャッシュ
+	feedCache := service.NewMemoryCache(5000)       // おすすめフィードの並び (閲覧のたびに増えるので生成結果を追い出さないよう分ける)
+	embeddingService := initEmbedding(ctx, llmService)
+	if vertex, ok := embeddingService.(*service.VertexEmbeddingService); ok {
+		defer vertex.Close()
 	}
-	defer geminiService.Close()
 
 	//DAO
 	userDAO := dao.NewUserDao(db)
 	productDAO := dao.NewProductDAO(db)
 	messageDAO := dao.NewMessageDao(db)
 	likeDAO := dao.NewLikeDao(db)
+	reportDAO := dao.NewReportDao(db)
+	auditLogDAO := dao.NewAuditLogDao(db)
+	moderationDAO := dao.NewModerationDao(db)
+	aiUsageDAO := dao.NewAIUsageDao(db)
+	translationDAO := dao.NewMessageTranslationDao(db)
+	embeddingDAO := dao.NewProductEmbeddingDao(db)
+	searchHistoryDAO := dao.NewSearchHistoryDao(db)
+	productEventDAO := dao.NewProductEventDao(db)
+	sellerAnalyticsDAO := dao.NewSellerAnalyticsDao(db)
+	followDAO := dao.NewFollowDao(db)
+	notificationDAO := dao.NewNotificationDao(db)
+	userStatsDAO := dao.NewUserStatsDao(db)
+	accountDAO := dao.NewAccountDao(db)
+	addressDAO := dao.NewAddressDao(db)
 
 	//Usecase
+	aiUsageUsecase := usecase.NewAIUsageUsecase(aiUsageDAO, aiUsageConfig())
+	moderationUsecase := usecase.NewModerationUsecase(moderationDAO, llmService)
+	similarProductUsecase := usecase.NewSimilarProductUsecase(productDAO, embeddingDAO, userDAO, storageService, embeddingService, aiUsageUsecase)
 	registerUsecase := usecase.NewRegisterUserUsecase(userDAO)
-	searchUsecase := usecase.NewSearchUserUsecase(userDAO)
-	productRegisterUsecase := usecase.NewProductRegisterUsecase(productDAO, userDAO, storageService)
-	productSearchUsecase := usecase.NewProductSearchUsecase(productDAO, userDAO, storageService)
+	notificationUsecase := usecase.NewNotificationUsecase(notificationDAO, userDAO)
+	userStatsUsecase := usecase.NewUserStatsUsecase(userStatsDAO, generationCache)
+	searchUsecase := usecase.NewSearchUserUsecase(userDAO, followDAO, userStatsUsecase)
+	productRegisterUsecase := usecase.NewProductRegisterUsecase(productDAO, userDAO, storageService, moderationUsecase, similarProductUsecase, notificationUsecase)
+	productSearchUsecase := usecase.NewProductSearchUsecase(productDAO, userDAO, storageService, searchHistoryDAO)
 	productDeleteUsecase := usecase.NewProductDeleteUsecase(productDAO, userDAO)
-	productUpdateUsecase := usecase.NewProductUpdateUsecase(productDAO, userDAO)
-	productDetailUsecase := usecase.NewProductDetailUsecase(productDAO, userDAO, storageService)
-	productPurchaseUsecase := usecase.NewProductPurchaseUsecase(productDAO, userDAO)
-	messageUsecase := usecase.NewMessageUsecase(messageDAO, userDAO)
-	productLikeUsecase := usecase.NewProductLikeUsecase(likeDAO, userDAO)
+	productUpdateUsecase := usecase.NewProductUpdateUsecase(productDAO, userDAO, moderationUsecase, similarProductUsecase)
+	productDetailUsecase := usecase.NewProductDetailUsecase(productDAO, userDAO, storageService, productEventDAO)
+	productPurchaseUsecase := usecase.NewProductPurchaseUsecase(productDAO, userDAO, addressDAO)
+	translationUsecase := usecase.NewTranslationUsecase(translationDAO, llmService)
+	messageUsecase := usecase.NewMessageUsecase(messageDAO, userDAO, moderationUsecase, translationUsecase)
+	productLikeUsecase := usecase.NewProductLikeUsecase(likeDAO, userDAO, productEventDAO)
 	userUpdateUsecase := usecase.NewUserUpdateUsecase(userDAO, storageService)
-	productDescUsecase := usecase.NewProductDescriptionUsecase(geminiService)
+	productPriceUsecase := usecase.NewProductPriceUsecase(productDAO, llmService)
+	productDescUsecase := usecase.NewProductDescriptionUsecase(llmService, productPriceUsecase, generationCache)
+	reportUsecase := usecase.NewReportUsecase(reportDAO, userDAO, productDAO, messageDAO, auditLogDAO)
+	adminUsecase := usecase.NewAdminUsecase(userDAO, productDAO, messageDAO, auditLogDAO)
+	listingReviewUsecase := usecase.NewListingReviewUsecase(productDAO, userDAO, storageService, llmService)
+	replySuggestionUsecase := usecase.NewReplySuggestionUsecase(messageDAO, productDAO, userDAO, llmService)
+	trendingUsecase := usecase.NewTrendingUsecase(productEventDAO)
+	sellerAnalyticsUsecase := usecase.NewSellerAnalyticsUsecase(sellerAnalyticsDAO, userDAO)
+	followUsecase := usecase.NewFollowUsecase(followDAO, userDAO, productDAO, storageService)
+	accountUsecase := usecase.NewAccountUsecase(accountDAO, userDAO, productDAO, messageDAO, followDAO, searchHistoryDAO, addressDAO, storageService)
+	addressUsecase := usecase.NewAddressUsecase(addressDAO, userDAO, productDAO)
+	feedUsecase := usecase.NewFeedUsecase(productDAO, userDAO, searchHistoryDAO, followDAO, storageService, similarProductUsecase, feedCache)
 
 	//Controller
 	registerUserCtrl := controller.NewRegisterUserController(registerUsecase, authClient)
@@ -81,12 +115,27 @@ func main() {
 	productSearchCtrl := controller.NewProductSearchController(productSearchUsecase, authClient)
 	productDeleteCtrl := controller.NewProductDeleteController(productDeleteUsecase, authClient)
 	productUpdateCtrl := controller.NewProductUpdateController(productUpdateUsecase, authClient)
-	productDetailCtrl := controller.NewProductDetailController(productDetailUsecase, authClient)
+	productDetailCtrl := controller.NewProductDetailController(productDetailUsecase, authClient, envInt("TRUSTED_PROXY_HOPS", 0)) // TRUSTED_PROXY_HOPS: 手前のプロキシの数 (Cloud Run なら 1)
 	productPurchaseCtrl := controller.NewProductPurchaseController(productPurchaseUsecase, authClient)
 	messageCtrl := controller.NewMessageController(messageUsecase, authClient)
 	productLikeCtrl := controller.NewProductLikeController(productLikeUsecase, authClient)
 	userUpdateCtrl := controller.NewUserUpdateController(userUpdateUsecase, authClient)
 	productDescCtrl := controller.NewProductDescriptionController(productDescUsecase, authClient)
+	productPriceCtrl := controller.NewProductPriceController(productPriceUsecase, authClient)
+	reportCtrl := controller.NewReportController(reportUsecase, authClient)
+	adminCtrl := controller.NewAdminController(adminUsecase, authClient)
+	moderationCtrl := controller.NewModerationController(moderationUsecase, authClient)
+	aiUsageCtrl := controller.NewAIUsageController(aiUsageUsecase, authClient)
+	listingReviewCtrl := controller.NewListingReviewController(listingReviewUsecase, authClient)
+	replySuggestionCtrl := controller.NewReplySuggestionController(replySuggestionUsecase, authClient)
+	similarProductCtrl := controller.NewSimilarProductController(similarProductUsecase, authClient)
+	feedCtrl := controller.NewFeedController(feedUsecase, authClient)
+	sellerAnalyticsCtrl := controller.NewSellerAnalyticsController(sellerAnalyticsUsecase, authClient)
+	followCtrl := controller.NewFollowController(followUsecase, authClient)
+	notificationCtrl := controller.NewNotificationController(notificationUsecase, authClient)
+	accountCtrl := controller.NewAccountController(accountUsecase, authClient)
+	addressCtrl := controller.NewAddressController(addressUsecase, authClient)
+	authMw := controller.NewAuthMiddleware(searchUsecase, authClient)
 
 	// --- 3. ルーティング設定 ---
 	mux := router.NewRouter(
@@ -102,8 +151,28 @@ func main() {
 		productLikeCtrl,
 		userUpdateCtrl,
 		productDescCtrl,
+		productPriceCtrl,
+		reportCtrl,
+		adminCtrl,
+		moderationCtrl,
+		aiUsageCtrl,
+		listingReviewCtrl,
+		replySuggestionCtrl,
+		similarProductCtrl,
+		feedCtrl,
+		sellerAnalyticsCtrl,
+		followCtrl,
+		notificationCtrl,
+		accountCtrl,
+		addressCtrl,
+		authMw,
 	)
 
+	// 急上昇スコアを定期的に計算し直す
+	go trendingUsecase.Run(ctx, 15*time.Minute)
+	go similarProductUsecase.Run(ctx)
+	go addressUsecase.RunRedaction(ctx, time.Hour)
+
 	// シャットダウン処理のセットアップ
 	closeDBWithSysCall()
 
@@ -150,6 +219,86 @@ func initFirebase() *auth.Client {
 	return client
 }
 
+// initLLM: 環境変数 LLM_PROVIDER に応じてLLMの実装を選びます
+//   - gemini (デフォルト): Vertex AI の Gemini
+//   - openai: OpenAI互換API (OPENAI_BASE_URL, OPENAI_API_KEY, OPENAI_MODEL, OPENAI_EMBEDDING_MODEL)
+//   - fake: 外部APIを呼ばない固定応答 (ローカル開発・オフライン用)
+func initLLM(ctx context.Context) service.LLMService {
+	switch os.Getenv("LLM_PROVIDER") {
+	case "fake":
+		log.Println("LLM: using fake provider")
+		return service.NewFakeLLMService()
+	case "openai":
+		baseURL := os.Getenv("OPENAI_BASE_URL")
+		if baseURL == "" {
+			baseURL = "http://localhost:11434/v1" // ローカルの Ollama
+		}
+		modelName := os.Getenv("OPENAI_MODEL")
+		if modelName == "" {
+			modelName = "gpt-4o-mini"
+		}
+		log.Printf("LLM: using OpenAI-compatible provider at %s (%s)", baseURL, modelName)
+		embeddingModel := os.Getenv("OPENAI_EMBEDDING_MODEL")
+		if embeddingModel == "" {
+			embeddingModel = "text-embedding-3-small"
+		}
+		return service.NewOpenAIService(baseURL, os.Getenv("OPENAI_API_KEY"), modelName, embeddingModel)
+	default:
+		projectID := "term8-taichi-onishi"
+		location := "asia-northeast1"
+		modelName := "gemini-2.5-flash"
+		geminiService, err := service.NewGeminiService(ctx, projectID, location, modelName)
+		if err != nil {
+			log.Fatalf("failed to init gemini: %v", err)
+		}
+		return geminiService
+	}
+}
+
+// initEmbedding: 類似商品の検索に使う埋め込みの実装を選びます
+// openai / fake はLLMと同じ実装を使い、gemini の場合は Vertex AI の埋め込みモデルを使います
+func initEmbedding(ctx context.Context, llm service.LLMService) service.EmbeddingService {
+	if e, ok := llm.(service.EmbeddingService); ok {
+		return e
+	}
+	embeddingService, err := service.NewVertexEmbeddingService(ctx, "term8-taichi-onishi", "asia-northeast1", "text-multilingual-embedding-002")
+	if err != nil {
+		log.Fatalf("failed to init embedding: %v", err)
+	}
+	return embeddingService
+}
+
+// aiUsageConfig: AI系エンドポイントの利用制限と料金を環境変数から読みます
+//   - AI_DAILY_QUOTA: ユーザーごとの1日のリクエスト上限 (デフォルト 50)
+//   - AI_MAX_CONCURRENCY: サーバー全体の同時実行数 (デフォルト 4)
+//   - AI_MODERATION_PER_MINUTE: 自動審査つきの投稿のユーザーごとの1分あたりの上限 (デフォルト 20)
+//   - AI_INPUT_COST_PER_MTOK / AI_OUTPUT_COST_PER_MTOK: 100万トークンあたりの料金USD (デフォルトは gemini-2.5-flash)
+func aiUsageConfig() usecase.AIUsageConfig {
+	return usecase.AIUsageConfig{
+		DailyQuota:          envInt("AI_DAILY_QUOTA", 50),
+		MaxConcurrent:       envInt("AI_MAX_CONCURRENCY", 4),
+		ModerationPerMinute: envInt("AI_MODERATION_PER_MINUTE", 20),
+		InputCostPer:        envFloat("AI_INPUT_COST_PER_MTOK", 0.30),
+		OutputCostPer:       envFloat("AI_OUTPUT_COST_PER_MTOK", 2.50),
+	}
+}
+
+func envInt(key string, def int) int {
+	v, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || v <= 0 {
+		return def
+	}
+	return v
+}
+
+func envFloat(key string, def float64) float64 {
+	v, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil || v < 0 {
+		return def
+	}
+	return v
+}
+
 func closeDBWithSysCall() {
 	sig := make(chan os.Signal, 1)
 	signal.Notify(sig, syscall.SIGTERM, syscall.SIGINT)
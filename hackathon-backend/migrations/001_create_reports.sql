@@ -0,0 +1,17 @@
+-- 通報 (商品 / ユーザー / メッセージ)
+CREATE TABLE IF NOT EXISTS reports (
+    id           CHAR(26)     NOT NULL PRIMARY KEY,
+    reporter_id  CHAR(26)     NOT NULL,
+    target_type  VARCHAR(16)  NOT NULL,
+    target_id    CHAR(26)     NOT NULL,
+    reason       VARCHAR(32)  NOT NULL,
+    note         TEXT,
+    status       VARCHAR(16)  NOT NULL DEFAULT 'open',
+    moderator_id CHAR(26)     NULL,
+    resolution   TEXT,
+    created_at   DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at   DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    INDEX idx_reports_target (target_type, target_id, status),
+    INDEX idx_reports_status (status, created_at),
+    FOREIGN KEY (reporter_id) REFERENCES users(id)
+);
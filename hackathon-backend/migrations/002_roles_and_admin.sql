@@ -0,0 +1,22 @@
+-- ロール・アカウント状態
+ALTER TABLE users
+    ADD COLUMN role   VARCHAR(16) NOT NULL DEFAULT 'user',
+    ADD COLUMN status VARCHAR(16) NOT NULL DEFAULT 'active';
+
+-- 運営による出品の取り下げ
+ALTER TABLE products
+    ADD COLUMN taken_down_at DATETIME NULL;
+
+-- 管理操作の監査ログ
+CREATE TABLE IF NOT EXISTS admin_audit_logs (
+    id          CHAR(26)    NOT NULL PRIMARY KEY,
+    actor_id    CHAR(26)    NOT NULL,
+    action      VARCHAR(32) NOT NULL,
+    target_type VARCHAR(16) NOT NULL,
+    target_id   CHAR(26)    NOT NULL,
+    detail      TEXT,
+    created_at  DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_audit_actor (actor_id, created_at),
+    INDEX idx_audit_target (target_type, target_id, created_at),
+    FOREIGN KEY (actor_id) REFERENCES users(id)
+);
@@ -0,0 +1,4 @@
+-- アカウント停止・BAN の理由と期限
+ALTER TABLE users
+    ADD COLUMN status_reason   TEXT     NULL,
+    ADD COLUMN suspended_until DATETIME NULL;
@@ -0,0 +1,13 @@
+-- 出品・メッセージの自動審査結果
+CREATE TABLE IF NOT EXISTS moderation_results (
+    id          CHAR(26)    NOT NULL PRIMARY KEY,
+    target_type VARCHAR(16) NOT NULL,
+    target_id   CHAR(26)    NOT NULL,
+    author_id   CHAR(26)    NOT NULL,
+    decision    VARCHAR(8)  NOT NULL,
+    categories  TEXT        NOT NULL,
+    reasons     TEXT        NOT NULL,
+    created_at  DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_moderation_target (target_type, target_id, created_at),
+    INDEX idx_moderation_decision (decision, created_at)
+);
@@ -0,0 +1,15 @@
+-- AI系エンドポイントの呼び出し記録 (利用額の集計に使う。日次上限は 015 の ai_daily_quota で数える)
+CREATE TABLE IF NOT EXISTS ai_usage_logs (
+    id                CHAR(26)    NOT NULL PRIMARY KEY,
+    user_id           CHAR(26)    NOT NULL,
+    endpoint          VARCHAR(64) NOT NULL,
+    model             VARCHAR(64) NOT NULL,
+    status            VARCHAR(8)  NOT NULL,
+    latency_ms        INT         NOT NULL,
+    calls             INT         NOT NULL,
+    prompt_tokens     INT         NOT NULL,
+    completion_tokens INT         NOT NULL,
+    created_at        DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_ai_usage_user (user_id, created_at),
+    INDEX idx_ai_usage_created (created_at)
+);
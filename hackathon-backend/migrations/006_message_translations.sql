@@ -0,0 +1,13 @@
+-- 翻訳の表示言語 (未設定は NULL)
+ALTER TABLE users
+    ADD COLUMN preferred_language VARCHAR(8) NULL;
+
+-- メッセージの翻訳結果 (メッセージ・言語ごとに1回だけ翻訳する)
+CREATE TABLE IF NOT EXISTS message_translations (
+    message_id      CHAR(26)   NOT NULL,
+    language        VARCHAR(8) NOT NULL,
+    source_language VARCHAR(8) NOT NULL,
+    translated_text TEXT       NOT NULL,
+    created_at      DATETIME   NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (message_id, language)
+);
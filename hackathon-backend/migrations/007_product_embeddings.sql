@@ -0,0 +1,9 @@
+-- 類似商品の検索に使う商品ごとの埋め込みベクトル
+-- vector は float32 (リトルエンディアン) を dims 個並べたもの
+CREATE TABLE IF NOT EXISTS product_embeddings (
+    product_id CHAR(26)     NOT NULL PRIMARY KEY,
+    model      VARCHAR(100) NOT NULL,
+    dims       INT          NOT NULL,
+    vector     MEDIUMBLOB   NOT NULL,
+    updated_at DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
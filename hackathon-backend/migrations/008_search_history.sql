@@ -0,0 +1,8 @@
+-- ログイン中のユーザーの検索ワード (おすすめフィードに使う)
+CREATE TABLE IF NOT EXISTS search_history (
+    id         BIGINT       NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    user_id    CHAR(26)     NOT NULL,
+    query      VARCHAR(255) NOT NULL,
+    created_at DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_search_history_user (user_id, created_at)
+);
@@ -0,0 +1,15 @@
+-- 商品の閲覧・いいねの記録 (急上昇スコアの計算に使う)
+CREATE TABLE IF NOT EXISTS product_events (
+    id         BIGINT      NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    product_id CHAR(26)    NOT NULL,
+    user_id    CHAR(26)    NULL, -- 未ログインの閲覧は NULL
+    event_type VARCHAR(16) NOT NULL, -- view / like
+    created_at DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_product_events_created (created_at),
+    INDEX idx_product_events_product (product_id, event_type, user_id)
+);
+
+-- 定期的に計算した急上昇スコア (検索のたびに計算しない)
+ALTER TABLE products
+    ADD COLUMN trending_score DOUBLE NOT NULL DEFAULT 0,
+    ADD INDEX idx_products_trending (trending_score);
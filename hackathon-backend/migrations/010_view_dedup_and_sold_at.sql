@@ -0,0 +1,10 @@
+-- 閲覧は閲覧者ごと・1日1回だけ数える
+-- viewer_key: ログイン中は "user:<ユーザーID>"、未ログインはIPとUser-Agentのハッシュ
+ALTER TABLE product_events
+    ADD COLUMN viewer_key VARCHAR(80) NULL,
+    ADD COLUMN view_date DATE NULL,
+    ADD UNIQUE KEY uq_product_events_daily_view (product_id, event_type, viewer_key, view_date);
+
+-- 売れた日時 (出品者の分析で期間ごとの成約数を出すため。以前に売れた商品は NULL)
+ALTER TABLE products
+    ADD COLUMN sold_at DATETIME NULL;
@@ -0,0 +1,8 @@
+-- いいね数を商品に持たせる (一覧のたびに likes を数えない)
+-- いいね・解除と同じトランザクションで更新する
+ALTER TABLE products
+    ADD COLUMN like_count INT NOT NULL DEFAULT 0,
+    ADD INDEX idx_products_like_count (like_count);
+
+UPDATE products p
+SET p.like_count = (SELECT COUNT(*) FROM likes l WHERE l.product_id = p.id);
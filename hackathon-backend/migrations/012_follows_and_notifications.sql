@@ -0,0 +1,22 @@
+-- フォロー (follower_id が followee_id をフォローしている)
+CREATE TABLE IF NOT EXISTS follows (
+    follower_id CHAR(26) NOT NULL,
+    followee_id CHAR(26) NOT NULL,
+    created_at  DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (follower_id, followee_id),
+    INDEX idx_follows_followee (followee_id, created_at),
+    FOREIGN KEY (follower_id) REFERENCES users(id),
+    FOREIGN KEY (followee_id) REFERENCES users(id)
+);
+
+-- お知らせ (今はフォロー中の出品者の新着出品のみ)
+CREATE TABLE IF NOT EXISTS notifications (
+    id         BIGINT      NOT NULL AUTO_INCREMENT PRIMARY KEY,
+    user_id    CHAR(26)    NOT NULL, -- 受け取る人
+    type       VARCHAR(32) NOT NULL,
+    actor_id   CHAR(26)    NOT NULL, -- 出品した人など
+    product_id CHAR(26)    NULL,
+    is_read    BOOLEAN     NOT NULL DEFAULT FALSE,
+    created_at DATETIME    NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_notifications_user (user_id, created_at)
+);
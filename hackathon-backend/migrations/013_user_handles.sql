@@ -0,0 +1,5 @@
+-- ユーザーのハンドル (@で呼べる一意な名前。未設定は NULL)
+-- 小文字に揃えて保存するので、大文字小文字の違いでも重複しない
+ALTER TABLE users
+    ADD COLUMN handle VARCHAR(20) NULL,
+    ADD UNIQUE KEY uq_users_handle (handle);
@@ -0,0 +1,34 @@
+-- 住所録
+CREATE TABLE IF NOT EXISTS addresses (
+    id          CHAR(26)     NOT NULL PRIMARY KEY,
+    user_id     CHAR(26)     NOT NULL,
+    name        VARCHAR(50)  NOT NULL, -- 宛名
+    postal_code VARCHAR(8)   NOT NULL, -- 123-4567
+    prefecture  VARCHAR(10)  NOT NULL,
+    city        VARCHAR(100) NOT NULL,
+    line1       VARCHAR(200) NOT NULL, -- 町名・番地
+    line2       VARCHAR(200) NOT NULL DEFAULT '', -- 建物名・部屋番号
+    phone       VARCHAR(20)  NOT NULL DEFAULT '',
+    is_default  BOOLEAN      NOT NULL DEFAULT FALSE,
+    created_at  DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_addresses_user (user_id, created_at),
+    FOREIGN KEY (user_id) REFERENCES users(id)
+);
+
+-- 購入時の届け先 (住所録を後で変えても売れたときの住所が残るよう写しておく)
+-- 取引が終わったら住所を消して redacted_at を入れる
+CREATE TABLE IF NOT EXISTS sale_shipping_addresses (
+    product_id  CHAR(26)     NOT NULL PRIMARY KEY,
+    buyer_id    CHAR(26)     NOT NULL,
+    name        VARCHAR(50)  NOT NULL,
+    postal_code VARCHAR(8)   NOT NULL,
+    prefecture  VARCHAR(10)  NOT NULL,
+    city        VARCHAR(100) NOT NULL,
+    line1       VARCHAR(200) NOT NULL,
+    line2       VARCHAR(200) NOT NULL DEFAULT '',
+    phone       VARCHAR(20)  NOT NULL DEFAULT '',
+    created_at  DATETIME     NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    redacted_at DATETIME     NULL,
+    INDEX idx_sale_shipping_redact (redacted_at, created_at),
+    INDEX idx_sale_shipping_buyer (buyer_id)
+);
@@ -0,0 +1,8 @@
+-- AIの日次上限のカウンター
+-- AIを呼ぶ前にここで1回分を確保するので、同時に来たリクエストでも上限を超えない
+CREATE TABLE IF NOT EXISTS ai_daily_quota (
+    user_id CHAR(26) NOT NULL,
+    day     DATE     NOT NULL, -- サーバーのタイムゾーンでの日付
+    used    INT      NOT NULL DEFAULT 0,
+    PRIMARY KEY (user_id, day)
+);
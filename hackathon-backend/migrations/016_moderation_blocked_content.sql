@@ -0,0 +1,4 @@
+-- block で投稿されなかった内容 (投稿が残らないので、モデレーターが見られるようにここに保存する)
+-- allow / flag は投稿そのものが残るので NULL
+ALTER TABLE moderation_results
+    ADD COLUMN content TEXT NULL;
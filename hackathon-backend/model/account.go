@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// DeletedUserName: 退会したユーザーの表示名
+const DeletedUserName = "退会したユーザー"
+
+// AccountExport: GET /users/me/export で返す、本人のデータ一式
+type AccountExport struct {
+	ExportedAt    time.Time     `json:"exported_at"`
+	Profile       *User         `json:"profile"`
+	Listings      []*Product    `json:"listings"`  // 出品した商品 (取り下げられたものを含む)
+	Purchases     []*Product    `json:"purchases"` // 購入した商品
+	Likes         []*Product    `json:"likes"`
+	Messages      []*Message    `json:"messages"` // 送った・受け取ったメッセージ
+	Following     []*FollowUser `json:"following"`
+	SearchHistory []string      `json:"search_history"`
+	Addresses     []*Address    `json:"addresses"`
+}
@@ -0,0 +1,96 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// 住所録に登録できる件数
+const MaxAddressesPerUser = 10
+
+var (
+	postalCodePattern = regexp.MustCompile(`^(\d{3})-?(\d{4})$`)
+	phonePattern      = regexp.MustCompile(`^0\d{1,4}-?\d{1,4}-?\d{3,4}$`)
+)
+
+// AddressFields: 届け先の項目 (住所録と、購入時の写しで共通)
+type AddressFields struct {
+	Name       string `json:"name"` // 宛名
+	PostalCode string `json:"postal_code"`
+	Prefecture string `json:"prefecture"`
+	City       string `json:"city"`
+	Line1      string `json:"line1"` // 町名・番地
+	Line2      string `json:"line2"` // 建物名・部屋番号
+	Phone      string `json:"phone"`
+}
+
+// Address: 住所録の1件
+type Address struct {
+	ID string `json:"id"`
+	AddressFields
+	IsDefault bool      `json:"is_default"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// 住所の登録・更新のリクエスト
+type AddressReq struct {
+	AddressFields
+	IsDefault bool `json:"is_default"`
+}
+
+// Validate: 前後の空白を除き、郵便番号を 123-4567 の形に揃えてから値を確認する
+func (r *AddressReq) Validate() error {
+	f := &r.AddressFields
+	for _, s := range []*string{&f.Name, &f.PostalCode, &f.Prefecture, &f.City, &f.Line1, &f.Line2, &f.Phone} {
+		*s = strings.TrimSpace(*s)
+	}
+
+	if f.Name == "" || f.Prefecture == "" || f.City == "" || f.Line1 == "" {
+		return errors.New("name, prefecture, city and line1 are required")
+	}
+	m := postalCodePattern.FindStringSubmatch(f.PostalCode)
+	if m == nil {
+		return fmt.Errorf("invalid postal_code: %q", f.PostalCode)
+	}
+	f.PostalCode = m[1] + "-" + m[2]
+	if f.Phone != "" && !phonePattern.MatchString(f.Phone) {
+		return fmt.Errorf("invalid phone: %q", f.Phone)
+	}
+
+	limits := []struct {
+		field string
+		value string
+		max   int
+	}{
+		{"name", f.Name, 50},
+		{"prefecture", f.Prefecture, 10},
+		{"city", f.City, 100},
+		{"line1", f.Line1, 200},
+		{"line2", f.Line2, 200},
+	}
+	for _, l := range limits {
+		if n := utf8.RuneCountInString(l.value); n > l.max {
+			return fmt.Errorf("%s is too long: max %d chars, but got %d", l.field, l.max, n)
+		}
+	}
+	return nil
+}
+
+// SaleShipping: 売れた商品の届け先 (出品者だけが見られる)
+// 取引が終わると住所は消され、Redacted が true になります
+type SaleShipping struct {
+	ProductID string `json:"product_id"`
+	BuyerID   string `json:"buyer_id"`
+	AddressFields
+	CreatedAt time.Time `json:"created_at"`
+	Redacted  bool      `json:"redacted"`
+}
+
+// 購入のリクエスト
+type PurchaseReq struct {
+	AddressID string `json:"address_id"`
+}
@@ -0,0 +1,51 @@
+package model
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAddressReqValidate(t *testing.T) {
+	valid := func() AddressReq {
+		return AddressReq{AddressFields: AddressFields{
+			Name:       "山田 太郎",
+			PostalCode: "123-4567",
+			Prefecture: "東京都",
+			City:       "渋谷区",
+			Line1:      "1-2-3",
+		}}
+	}
+
+	tests := []struct {
+		name           string
+		modify         func(r *AddressReq)
+		wantErr        bool
+		wantPostalCode string
+	}{
+		{"valid", func(r *AddressReq) {}, false, "123-4567"},
+		{"postal code without hyphen", func(r *AddressReq) { r.PostalCode = "1234567" }, false, "123-4567"},
+		{"surrounding spaces are trimmed", func(r *AddressReq) { r.PostalCode = " 123-4567 " }, false, "123-4567"},
+		{"phone with hyphens", func(r *AddressReq) { r.Phone = "090-1234-5678" }, false, "123-4567"},
+		{"phone without hyphens", func(r *AddressReq) { r.Phone = "0312345678" }, false, "123-4567"},
+		{"missing name", func(r *AddressReq) { r.Name = "  " }, true, ""},
+		{"missing line1", func(r *AddressReq) { r.Line1 = "" }, true, ""},
+		{"short postal code", func(r *AddressReq) { r.PostalCode = "123-456" }, true, ""},
+		{"non-numeric postal code", func(r *AddressReq) { r.PostalCode = "abc-defg" }, true, ""},
+		{"phone not starting with 0", func(r *AddressReq) { r.Phone = "9012345678" }, true, ""},
+		{"name too long", func(r *AddressReq) { r.Name = strings.Repeat("あ", 51) }, true, ""},
+		{"name at the limit", func(r *AddressReq) { r.Name = strings.Repeat("あ", 50) }, false, "123-4567"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := valid()
+			tt.modify(&r)
+			err := r.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && r.PostalCode != tt.wantPostalCode {
+				t.Errorf("PostalCode = %q, want %q", r.PostalCode, tt.wantPostalCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// 監査ログのアクション
+const (
+	AuditActionSuspendUser    = "suspend_user"
+	AuditActionUnsuspendUser  = "unsuspend_user"
+	AuditActionBanUser        = "ban_user"
+	AuditActionChangeRole     = "change_role"
+	AuditActionTakeDown       = "take_down_product"
+	AuditActionRestore        = "restore_product"
+	AuditActionCancelPurchase = "cancel_purchase"
+	AuditActionViewChat       = "view_chat"
+	AuditActionUpdateReport   = "update_report"
+)
+
+// 監査ログの対象の種類 (ユーザー・商品は ReportTarget* と同じ値を使う)
+const AuditTargetReport = "report"
+
+// AuditLog: 管理操作の記録
+type AuditLog struct {
+	ID         string    `json:"id"`
+	ActorID    string    `json:"actor_id"`
+	Action     string    `json:"action"`
+	TargetType string    `json:"target_type"`
+	TargetID   string    `json:"target_id"`
+	Detail     string    `json:"detail"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type AuditLogPage struct {
+	Logs  []*AuditLog `json:"logs"`
+	Total int         `json:"total"`
+}
+
+// 管理操作の理由 (停止・取り下げ・キャンセルなど共通)
+type AdminActionReq struct {
+	Reason string `json:"reason"`
+}
+
+// 利用停止・BANのリクエスト用 (Until が nil なら無期限)
+type SuspendUserReq struct {
+	Reason string     `json:"reason"`
+	Until  *time.Time `json:"until"`
+}
+
+func (r *SuspendUserReq) Validate() error {
+	if r.Reason == "" {
+		return errors.New("reason is empty")
+	}
+	if r.Until != nil && !r.Until.After(time.Now()) {
+		return errors.New("until must be in the future")
+	}
+	return nil
+}
+
+// ロール変更のリクエスト用
+type UpdateRoleReq struct {
+	Role string `json:"role"`
+}
+
+func (r *UpdateRoleReq) Validate() error {
+	if !IsValidRole(r.Role) {
+		return fmt.Errorf("invalid role: %q", r.Role)
+	}
+	return nil
+}
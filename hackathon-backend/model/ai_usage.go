@@ -0,0 +1,44 @@
+package model
+
+import "time"
+
+// AI呼び出しの結果
+const (
+	AIUsageStatusSuccess = "success"
+	AIUsageStatusError   = "error"
+)
+
+// AIUsage: AI系エンドポイント1リクエスト分の使用量
+// 1リクエストで複数回LLMを呼んだ場合 (再試行など) は Calls とトークン数を合算します
+type AIUsage struct {
+	ID               string    `json:"id"`
+	UserID           string    `json:"user_id"`
+	Endpoint         string    `json:"endpoint"`
+	Model            string    `json:"model"`
+	Status           string    `json:"status"`
+	LatencyMs        int64     `json:"latency_ms"`
+	Calls            int       `json:"calls"`
+	PromptTokens     int       `json:"prompt_tokens"`
+	CompletionTokens int       `json:"completion_tokens"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// AISpendByUser: ユーザーごとのAI利用額
+type AISpendByUser struct {
+	UserID           string  `json:"user_id"`
+	UserName         string  `json:"user_name"`
+	Requests         int     `json:"requests"`
+	Calls            int     `json:"calls"`
+	PromptTokens     int     `json:"prompt_tokens"`
+	CompletionTokens int     `json:"completion_tokens"`
+	EstimatedCostUSD float64 `json:"estimated_cost_usd"`
+}
+
+// AISpendReport: 期間内のAI利用額 (利用額の多い順)
+type AISpendReport struct {
+	From             time.Time        `json:"from"`
+	To               time.Time        `json:"to"`
+	Users            []*AISpendByUser `json:"users"`
+	TotalRequests    int              `json:"total_requests"`
+	EstimatedCostUSD float64          `json:"estimated_cost_usd"`
+}
@@ -0,0 +1,17 @@
+package model
+
+// フィードの並べ方
+const (
+	FeedSourcePersonalized = "personalized" // いいね・購入・フォロー・検索履歴から並べた
+	FeedSourceTrending     = "trending"     // 履歴がないので急上昇順 (コールドスタート)
+)
+
+// FeedPage: おすすめフィードの1ページ
+// 2ページ目以降は feed_id を付けて取得すると、1ページ目と同じ並びの続きを返します
+type FeedPage struct {
+	Products []*Product `json:"products"`
+	FeedID   string     `json:"feed_id"`
+	Page     int        `json:"page"`
+	HasMore  bool       `json:"has_more"`
+	Source   string     `json:"source"` // personalized / trending
+}
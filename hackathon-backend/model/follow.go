@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// UserProfile: GET /users/{id} のレスポンス (公開してよいユーザー情報にフォローの情報と実績を足したもの)
+type UserProfile struct {
+	*PublicUser
+	FollowerCount  int        `json:"follower_count"`
+	FollowingCount int        `json:"following_count"`
+	IsFollowing    bool       `json:"is_following"` // 見ている人がフォローしているか (未ログインは false)
+	Stats          *UserStats `json:"stats"`        // 集計に失敗したときは null
+}
+
+// FollowUser: フォロー・フォロワー一覧の1人分
+type FollowUser struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Handle     string    `json:"handle"`
+	ImageURL   string    `json:"image_url"`
+	Bio        string    `json:"bio"`
+	FollowedAt time.Time `json:"followed_at"`
+}
+
+type FollowUserPage struct {
+	Users []*FollowUser `json:"users"`
+	Total int           `json:"total"`
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ハンドルの長さ (ULIDは26文字なので、ハンドルとIDは見分けられる)
+const (
+	HandleMinLength = 3
+	HandleMaxLength = 20
+)
+
+// 英小文字で始まり、英小文字・数字・_ だけ
+var handlePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// 使わせないハンドル (URLの一部や、運営と紛らわしいもの)
+var reservedHandles = map[string]bool{
+	"me": true, "admin": true, "administrator": true, "moderator": true, "root": true,
+	"system": true, "support": true, "help": true, "official": true, "staff": true,
+	"api": true, "users": true, "products": true, "messages": true, "notifications": true,
+	"followers": true, "following": true, "settings": true, "login": true, "logout": true,
+	"signup": true, "register": true, "search": true, "null": true, "undefined": true,
+}
+
+// NormalizeHandle: 先頭の @ と前後の空白を外して小文字にする
+func NormalizeHandle(handle string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(handle), "@"))
+}
+
+// ValidateHandle: 正規化済みのハンドルが使えるか
+func ValidateHandle(handle string) error {
+	if len(handle) < HandleMinLength || len(handle) > HandleMaxLength {
+		return fmt.Errorf("handle must be between %d and %d characters, but got %d", HandleMinLength, HandleMaxLength, len(handle))
+	}
+	if !handlePattern.MatchString(handle) {
+		return fmt.Errorf("handle must start with a letter and contain only letters, digits and underscores")
+	}
+	if reservedHandles[handle] {
+		return fmt.Errorf("handle %q is reserved", handle)
+	}
+	return nil
+}
+
+// UserSummary: ユーザー検索の1人分 (公開してよい項目だけ)
+type UserSummary struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Handle   string `json:"handle"`
+	ImageURL string `json:"image_url"`
+	Bio      string `json:"bio"`
+}
+
+type UserSummaryPage struct {
+	Users []*UserSummary `json:"users"`
+	Total int            `json:"total"`
+}
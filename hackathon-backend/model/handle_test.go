@@ -0,0 +1,50 @@
+package model
+
+import "testing"
+
+func TestNormalizeHandle(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"taro", "taro"},
+		{"@taro", "taro"},
+		{"  @Taro_01 ", "taro_01"},
+		{"TARO", "taro"},
+		{"@@taro", "@taro"}, // 外すのは先頭の1つだけ
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeHandle(tt.in); got != tt.want {
+			t.Errorf("NormalizeHandle(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestValidateHandle(t *testing.T) {
+	tests := []struct {
+		handle  string
+		wantErr bool
+	}{
+		{"taro", false},
+		{"taro_01", false},
+		{"abc", false},
+		{"abcdefghijklmnopqrst", false}, // 20文字
+		{"ab", true},                    // 短すぎる
+		{"abcdefghijklmnopqrstu", true}, // 21文字
+		{"1taro", true},                 // 数字で始まる
+		{"_taro", true},
+		{"taro-01", true},
+		{"Taro", true}, // 正規化前の大文字
+		{"たろう", true},
+		{"admin", true}, // 予約語
+		{"me", true},
+		{"users", true},
+	}
+	for _, tt := range tests {
+		err := ValidateHandle(tt.handle)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateHandle(%q) error = %v, wantErr %v", tt.handle, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package model
+
+// 改善提案の対象
+const (
+	ListingFieldTitle       = "title"
+	ListingFieldDescription = "description"
+	ListingFieldPhoto       = "photo"
+	ListingFieldPrice       = "price"
+)
+
+// 改善提案の出どころ
+const (
+	SuggestionSourceCheck = "check" // 文字数や画像の明るさなどの機械的なチェック
+	SuggestionSourceAI    = "ai"
+)
+
+// ListingReview: 出品内容の採点結果 (各スコアは0〜100)
+type ListingReview struct {
+	Score            int                  `json:"score"`
+	TitleScore       int                  `json:"title_score"`
+	DescriptionScore int                  `json:"description_score"`
+	PhotoScore       int                  `json:"photo_score"`
+	Suggestions      []*ListingSuggestion `json:"suggestions"`
+	AIReviewed       bool                 `json:"ai_reviewed"` // false ならAIが使えず機械的なチェックのみ
+}
+
+// ListingSuggestion: 出品内容の改善提案
+type ListingSuggestion struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+	Source  string `json:"source"`
+}
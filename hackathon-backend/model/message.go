@@ -12,6 +12,16 @@ type Message struct {
 	IsRead      bool      `json:"is_read"`
 	IsDeleted   bool      `json:"is_deleted"`
 	CreatedAt   time.Time `json:"created_at"`
+	// 翻訳 (GET /messages?translate=true のときだけ、相手からのメッセージに付く)
+	Translation *MessageTranslation `json:"translation,omitempty"`
+}
+
+// MessageTranslation: メッセージの翻訳結果
+type MessageTranslation struct {
+	MessageID      string `json:"-"`
+	Language       string `json:"language"`
+	SourceLanguage string `json:"source_language"` // 原文の言語 (language と同じなら翻訳不要だった)
+	Text           string `json:"text"`
 }
 
 // 送信するときのリクエスト用
@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// 自動審査の判定
+const (
+	ModerationAllow = "allow" // 問題なし
+	ModerationFlag  = "flag"  // 要確認 (投稿は通すがモデレーターに見せる)
+	ModerationBlock = "block" // 投稿させない
+)
+
+// 自動審査のカテゴリ
+const (
+	ModerationCategoryProhibited  = "prohibited_item" // 出品禁止物
+	ModerationCategoryContactInfo = "contact_info"    // 個人の連絡先の交換
+	ModerationCategoryAbusive     = "abusive"         // 暴言・嫌がらせ
+)
+
+// ModerationResult: 出品・メッセージの自動審査結果
+type ModerationResult struct {
+	ID         string    `json:"id"`
+	TargetType string    `json:"target_type"` // product / message
+	TargetID   string    `json:"target_id"`   // block の新規投稿では保存されなかった下書きのID
+	AuthorID   string    `json:"author_id"`
+	Decision   string    `json:"decision"`
+	Categories []string  `json:"categories"`
+	Reasons    []string  `json:"reasons"`
+	Content    string    `json:"content,omitempty"` // block されて投稿されなかった内容
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type ModerationResultPage struct {
+	Results []*ModerationResult `json:"results"`
+	Total   int                 `json:"total"`
+}
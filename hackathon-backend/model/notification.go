@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// お知らせの種類
+const (
+	NotificationNewListing = "new_listing" // フォロー中の出品者が出品した
+)
+
+type Notification struct {
+	ID            int64     `json:"id"`
+	Type          string    `json:"type"`
+	ActorID       string    `json:"actor_id"`
+	ActorName     string    `json:"actor_name"`
+	ActorImageURL string    `json:"actor_image_url"`
+	ProductID     string    `json:"product_id,omitempty"`
+	ProductName   string    `json:"product_name,omitempty"`
+	IsRead        bool      `json:"is_read"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+type NotificationPage struct {
+	Notifications []*Notification `json:"notifications"`
+	UnreadCount   int             `json:"unread_count"`
+}
@@ -1,6 +1,10 @@
 package model
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 type Product struct {
 	ID            string    `json:"id"`
@@ -17,11 +21,14 @@ type Product struct {
 	BuyerName     string    `json:"buyer_name"`
 	UserImageURL  string    `json:"user_image_url"`
 	BuyerImageURL string    `json:"buyer_image_url"`
+	IsTakenDown   bool      `json:"is_taken_down"`
 }
 
 type ProductPage struct {
 	Products []*Product `json:"products"`
 	Total    int        `json:"total"`
+	// 自然文検索 (mode=natural) のときに、読み取った検索条件を返す
+	Interpreted *SearchQuery `json:"interpreted,omitempty"`
 }
 
 type ProductReq struct {
@@ -30,15 +37,83 @@ type ProductReq struct {
 	Description string `json:"description"`
 }
 
+// 説明文の口調
+const (
+	ToneFormal       = "formal"
+	ToneCasual       = "casual"
+	ToneEnthusiastic = "enthusiastic"
+)
+
+// 説明文・翻訳の言語
+const (
+	LanguageJa = "ja"
+	LanguageEn = "en"
+	LanguageZh = "zh"
+)
+
+// IsValidLanguage: 対応している言語か
+func IsValidLanguage(lang string) bool {
+	switch lang {
+	case LanguageJa, LanguageEn, LanguageZh:
+		return true
+	}
+	return false
+}
+
 // AI商品説明生成のリクエスト
+// name 以外は省略可 (省略時は丁寧語・日本語・200文字・1案)
 type GenerateReq struct {
-	Name     string `json:"name"`
-	Keywords string `json:"keywords"`
+	Name            string `json:"name"`
+	Keywords        string `json:"keywords"`
+	Tone            string `json:"tone"`             // formal / casual / enthusiastic
+	Length          int    `json:"length"`           // 目安の文字数 (50〜800)
+	Language        string `json:"language"`         // ja / en / zh
+	IncludeShipping bool   `json:"include_shipping"` // 商品の状態・発送についての段落を入れるか
+	Drafts          int    `json:"drafts"`           // 候補の数 (1〜3)
+	PromptVersion   string `json:"prompt_version"`   // 省略時は最新のテンプレート
+}
+
+// Validate: 省略された項目に既定値を入れてから値を確認する
+func (r *GenerateReq) Validate() error {
+	if r.Name == "" {
+		return errors.New("name is empty")
+	}
+	if r.Tone == "" {
+		r.Tone = ToneFormal
+	}
+	if r.Length == 0 {
+		r.Length = 200
+	}
+	if r.Language == "" {
+		r.Language = LanguageJa
+	}
+	if r.Drafts == 0 {
+		r.Drafts = 1
+	}
+
+	switch r.Tone {
+	case ToneFormal, ToneCasual, ToneEnthusiastic:
+	default:
+		return fmt.Errorf("invalid tone: %q", r.Tone)
+	}
+	if r.Length < 50 || r.Length > 800 {
+		return fmt.Errorf("length must be between 50 and 800, but got %d", r.Length)
+	}
+	if !IsValidLanguage(r.Language) {
+		return fmt.Errorf("invalid language: %q", r.Language)
+	}
+	if r.Drafts < 1 || r.Drafts > 3 {
+		return fmt.Errorf("drafts must be between 1 and 3, but got %d", r.Drafts)
+	}
+	return nil
 }
 
 // AI商品説明生成のレスポンス
 type GenerateRes struct {
-	Description string `json:"description"`
+	Description   string   `json:"description"` // 1つ目の案 (drafts[0] と同じ)
+	Drafts        []string `json:"drafts"`
+	PromptVersion string   `json:"prompt_version"`
+	Cached        bool     `json:"cached"` // キャッシュから返した結果か
 }
 
 type GenerateImageRes struct {
@@ -46,4 +121,39 @@ type GenerateImageRes struct {
 	Price       int    `json:"price"`
 	Keywords    string `json:"keywords"`
 	Description string `json:"description"`
+	Cached      bool   `json:"cached"` // AIの解析結果をキャッシュから返したか
+	// price の根拠 ("market": 過去の取引実績, "ai": AIの推定)
+	PriceSource     string           `json:"price_source"`
+	PriceSuggestion *PriceSuggestion `json:"price_suggestion,omitempty"`
+}
+
+// 価格提案のリクエスト
+type SuggestPriceReq struct {
+	Name     string `json:"name"`
+	Keywords string `json:"keywords"`
+	Explain  bool   `json:"explain"` // true ならAIによる説明文も付ける
+}
+
+// PriceSuggestion: 売れた類似商品から計算した価格の目安
+type PriceSuggestion struct {
+	Min         int                  `json:"min"` // 目安の下限 (件数が多ければ第1四分位)
+	Max         int                  `json:"max"` // 目安の上限 (件数が多ければ第3四分位)
+	Median      int                  `json:"median"`
+	SampleSize  int                  `json:"sample_size"`
+	Comparables []*ComparableProduct `json:"comparables"`
+	Explanation string               `json:"explanation,omitempty"`
+}
+
+// ComparableProduct: 価格の根拠にした売れた商品
+type ComparableProduct struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Price     int       `json:"price"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// FieldError: 項目ごとのバリデーションエラー
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
 }
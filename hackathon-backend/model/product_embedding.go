@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// ProductEmbedding: 商品の埋め込みベクトル (類似商品の検索用)
+type ProductEmbedding struct {
+	ProductID string
+	Model     string
+	Vector    []float32
+	UpdatedAt time.Time
+}
+
+// 類似商品の求め方
+const (
+	SimilarSourceEmbedding = "embedding" // 埋め込みベクトルの近さ
+	SimilarSourceKeyword   = "keyword"   // 商品名のキーワードの一致 (ベクトルがない場合)
+)
+
+// SimilarProductsRes: GET /products/{id}/similar のレスポンス
+type SimilarProductsRes struct {
+	Products []*Product `json:"products"`
+	Source   string     `json:"source"` // embedding / keyword
+}
@@ -0,0 +1,7 @@
+package model
+
+// 商品への反応の種類
+const (
+	ProductEventView = "view"
+	ProductEventLike = "like"
+)
@@ -0,0 +1,7 @@
+package model
+
+// SuggestedRepliesRes: チャットの返信候補 (送信はしない。利用者が選んで編集してから送る)
+type SuggestedRepliesRes struct {
+	Replies   []string `json:"replies"`
+	ProductID string   `json:"product_id,omitempty"` // 参考にした商品
+}
@@ -0,0 +1,96 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// 通報対象の種類
+const (
+	ReportTargetProduct = "product"
+	ReportTargetUser    = "user"
+	ReportTargetMessage = "message"
+)
+
+// 通報理由コード
+const (
+	ReportReasonCounterfeit = "counterfeit" // 偽物・コピー品
+	ReportReasonProhibited  = "prohibited"  // 出品禁止物
+	ReportReasonAbusive     = "abusive"     // 暴言・嫌がらせ
+	ReportReasonSpam        = "spam"        // スパム・宣伝
+	ReportReasonOther       = "other"       // その他
+)
+
+// 通報の対応状況
+const (
+	ReportStatusOpen      = "open"      // 未対応
+	ReportStatusReviewing = "reviewing" // 確認中 (商品は検索結果から非表示)
+	ReportStatusResolved  = "resolved"  // 対応済み
+	ReportStatusDismissed = "dismissed" // 問題なし
+)
+
+type Report struct {
+	ID          string    `json:"id"`
+	ReporterID  string    `json:"reporter_id"`
+	TargetType  string    `json:"target_type"`
+	TargetID    string    `json:"target_id"`
+	Reason      string    `json:"reason"`
+	Note        string    `json:"note"`
+	Status      string    `json:"status"`
+	ModeratorID string    `json:"moderator_id,omitempty"`
+	Resolution  string    `json:"resolution,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type ReportPage struct {
+	Reports []*Report `json:"reports"`
+	Total   int       `json:"total"`
+}
+
+// 通報するときのリクエスト用
+type CreateReportReq struct {
+	TargetType string `json:"target_type"`
+	TargetID   string `json:"target_id"`
+	Reason     string `json:"reason"`
+	Note       string `json:"note"`
+}
+
+func (r *CreateReportReq) Validate() error {
+	switch r.TargetType {
+	case ReportTargetProduct, ReportTargetUser, ReportTargetMessage:
+	default:
+		return fmt.Errorf("invalid target_type: %q", r.TargetType)
+	}
+	if r.TargetID == "" {
+		return errors.New("target_id is empty")
+	}
+	switch r.Reason {
+	case ReportReasonCounterfeit, ReportReasonProhibited, ReportReasonAbusive, ReportReasonSpam, ReportReasonOther:
+	default:
+		return fmt.Errorf("invalid reason: %q", r.Reason)
+	}
+	if r.Reason == ReportReasonOther && r.Note == "" {
+		return errors.New("note is required when reason is other")
+	}
+	if len([]rune(r.Note)) > 1000 {
+		return fmt.Errorf("note is too long: max 1000 chars, but got %d", len([]rune(r.Note)))
+	}
+	return nil
+}
+
+// モデレーターが通報を更新するときのリクエスト用
+type UpdateReportReq struct {
+	Status     string `json:"status"`
+	Resolution string `json:"resolution"`
+}
+
+func (r *UpdateReportReq) Validate() error {
+	switch r.Status {
+	case ReportStatusReviewing, ReportStatusResolved, ReportStatusDismissed:
+	default:
+		return fmt.Errorf("invalid status: %q", r.Status)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package model
+
+// ProductFilter: 商品一覧・検索の条件 (DAOに渡す)
+type ProductFilter struct {
+	Keyword      string     // 商品名の部分一致 (従来の q)
+	TermGroups   [][]string // 各グループのどれかを商品名か説明文に含む (グループ同士は AND)
+	MinPrice     int        // 0 は指定なし
+	MaxPrice     int        // 0 は指定なし
+	Status       string     // selling / sold / "" (すべて)
+	TargetUserID string     // 出品者で絞り込む場合
+	FollowedBy   string     // このユーザーがフォローしている出品者の商品に絞り込む場合
+}
+
+// 検索条件の属性名
+const (
+	SearchAttrColor = "color"
+	SearchAttrSize  = "size"
+)
+
+// SearchAttribute: 色・サイズなどの属性 (商品名か説明文に含まれているかで判定する)
+type SearchAttribute struct {
+	Name  string `json:"name"`  // color / size
+	Value string `json:"value"` // white / 27 など
+}
+
+// SearchQuery: 検索ボックスの文章から読み取った条件
+// UIで表示・編集し、編集後は同じ項目をクエリパラメータで送り返します
+type SearchQuery struct {
+	Text       string            `json:"text"`
+	Keywords   []string          `json:"keywords"`
+	MinPrice   int               `json:"min_price,omitempty"`
+	MaxPrice   int               `json:"max_price,omitempty"`
+	Attributes []SearchAttribute `json:"attributes"`
+	Status     string            `json:"status,omitempty"`
+	Sort       string            `json:"sort,omitempty"`
+}
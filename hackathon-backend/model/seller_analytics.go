@@ -0,0 +1,47 @@
+package model
+
+import "time"
+
+// ListingAnalytics: 出品した商品1件の期間内の反応
+type ListingAnalytics struct {
+	ProductID      string     `json:"product_id"`
+	Name           string     `json:"name"`
+	Price          int        `json:"price"`
+	CreatedAt      time.Time  `json:"created_at"`
+	IsSold         bool       `json:"is_sold"`
+	SoldAt         *time.Time `json:"sold_at,omitempty"`
+	Views          int        `json:"views"`           // 閲覧者ごとに1日1回
+	Likes          int        `json:"likes"`           // 期間内に付いたいいね
+	MessageThreads int        `json:"message_threads"` // 期間内にメッセージのやり取りがあった相手の数
+	LikeRate       float64    `json:"like_rate"`       // likes / views
+	InquiryRate    float64    `json:"inquiry_rate"`    // message_threads / views
+}
+
+// AnalyticsDay: 日ごとの閲覧・いいね・成約の数
+type AnalyticsDay struct {
+	Date  string `json:"date"` // 2006-01-02
+	Views int    `json:"views"`
+	Likes int    `json:"likes"`
+	Sales int    `json:"sales"`
+}
+
+// AnalyticsTotals: 期間内の合計
+type AnalyticsTotals struct {
+	Listings       int     `json:"listings"`
+	Views          int     `json:"views"`
+	Likes          int     `json:"likes"`
+	MessageThreads int     `json:"message_threads"`
+	Sales          int     `json:"sales"`
+	LikeRate       float64 `json:"like_rate"`       // likes / views
+	InquiryRate    float64 `json:"inquiry_rate"`    // message_threads / views
+	ConversionRate float64 `json:"conversion_rate"` // sales / views
+}
+
+// SellerAnalytics: GET /users/me/analytics のレスポンス
+type SellerAnalytics struct {
+	From     time.Time           `json:"from"`
+	To       time.Time           `json:"to"`
+	Totals   AnalyticsTotals     `json:"totals"`
+	Daily    []*AnalyticsDay     `json:"daily"`
+	Listings []*ListingAnalytics `json:"listings"`
+}
@@ -3,15 +3,96 @@ package model
 import (
 	"errors"
 	"fmt"
+	"time"
+)
+
+// ロール (下に行くほど強い権限)
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+// アカウント状態
+const (
+	UserStatusActive    = "active"
+	UserStatusSuspended = "suspended" // 期限付きの利用停止
+	UserStatusBanned    = "banned"    // 無期限の利用停止
+	UserStatusDeleted   = "deleted"   // 退会済み (プロフィールは匿名化されている)
 )
 
 type User struct {
 	ID          string `json:"id"`
 	Name        string `json:"name"`
+	Handle      string `json:"handle"` // 未設定は空
 	FirebaseUID string `json:"firebase_uid"`
 	Bio         string `json:"bio"`
 	ImageURL    string `json:"image_url"`
+	Role        string `json:"role"`
+	Status      string `json:"status"`
+	// 停止・BANの理由と期限 (期限なしは nil)
+	StatusReason   string     `json:"status_reason,omitempty"`
+	SuspendedUntil *time.Time `json:"suspended_until,omitempty"`
+	// メッセージ翻訳の表示言語 (ja / en / zh。未設定は空)
+	PreferredLanguage string `json:"preferred_language"`
+}
+
+// PublicUser: ほかの人に見せてよいユーザー情報
+// Firebase UID・言語設定・利用停止の理由や期限などは含めません
+type PublicUser struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Handle   string `json:"handle"` // 未設定は空
+	Bio      string `json:"bio"`
+	ImageURL string `json:"image_url"`
+}
+
+// Public: 公開してよい項目だけを取り出す
+func (u *User) Public() *PublicUser {
+	return &PublicUser{
+		ID:       u.ID,
+		Name:     u.Name,
+		Handle:   u.Handle,
+		Bio:      u.Bio,
+		ImageURL: u.ImageURL,
+	}
+}
+
+// IsRestricted: 現在、書き込み操作が制限されているか
+// suspended でも期限を過ぎていれば制限なしとして扱います
+func (u *User) IsRestricted(now time.Time) bool {
+	switch u.Status {
+	case UserStatusBanned, UserStatusDeleted:
+		return true
+	case UserStatusSuspended:
+		return u.SuspendedUntil == nil || now.Before(*u.SuspendedUntil)
+	}
+	return false
+}
+
+// ロールの強さ (未知のロールは0)
+func roleLevel(role string) int {
+	switch role {
+	case RoleUser:
+		return 1
+	case RoleModerator:
+		return 2
+	case RoleAdmin:
+		return 3
+	}
+	return 0
 }
+
+// IsValidRole: 定義済みのロールか
+func IsValidRole(role string) bool {
+	return roleLevel(role) > 0
+}
+
+// HasRole: required 以上の権限を持っているか (admin は moderator の操作もできる)
+func (u *User) HasRole(required string) bool {
+	return roleLevel(u.Role) >= roleLevel(required) && roleLevel(required) > 0
+}
+
 type CreateUserReq struct {
 	Name string `json:"name"`
 }
@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+// UserStats: プロフィールに出す公開の実績 (定期的に計算し直したもの)
+type UserStats struct {
+	ListedCount int `json:"listed_count"` // 出品した数 (削除された商品は除く)
+	SoldCount   int `json:"sold_count"`
+	BoughtCount int `json:"bought_count"`
+	// 取引の評価 (評価機能がまだないため、今は常に null と 0)
+	AverageRating *float64 `json:"average_rating"`
+	RatingCount   int      `json:"rating_count"`
+
+	MemberSince  time.Time  `json:"member_since"`
+	LastActiveAt *time.Time `json:"last_active_at"` // 出品・メッセージ送信・閲覧・いいねのうち最後のもの
+	// チャットで相手からのメッセージに返信するまでの時間の中央値 (分)。返信の実績が少なければ null
+	ResponseTimeMinutes *int `json:"response_time_minutes"`
+
+	ComputedAt time.Time `json:"computed_at"` // この集計をした時刻
+}
+
+// MessageTiming: 返信時間の計算に使うメッセージの送信者・受信者・時刻
+type MessageTiming struct {
+	SenderID   string
+	ReceiverID string
+	CreatedAt  time.Time
+}
@@ -0,0 +1,57 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUserIsRestricted(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	past := now.Add(-time.Hour)
+	future := now.Add(time.Hour)
+
+	tests := []struct {
+		name string
+		user User
+		want bool
+	}{
+		{"active", User{Status: UserStatusActive}, false},
+		{"banned", User{Status: UserStatusBanned}, true},
+		{"deleted", User{Status: UserStatusDeleted}, true},
+		{"suspended without end", User{Status: UserStatusSuspended}, true},
+		{"suspended until future", User{Status: UserStatusSuspended, SuspendedUntil: &future}, true},
+		{"suspension expired", User{Status: UserStatusSuspended, SuspendedUntil: &past}, false},
+		{"suspension ends exactly now", User{Status: UserStatusSuspended, SuspendedUntil: &now}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.user.IsRestricted(now); got != tt.want {
+				t.Errorf("IsRestricted() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserHasRole(t *testing.T) {
+	tests := []struct {
+		role     string
+		required string
+		want     bool
+	}{
+		{RoleUser, RoleUser, true},
+		{RoleUser, RoleModerator, false},
+		{RoleModerator, RoleModerator, true},
+		{RoleModerator, RoleAdmin, false},
+		{RoleAdmin, RoleModerator, true}, // admin は moderator の操作もできる
+		{RoleAdmin, RoleAdmin, true},
+		{"", RoleUser, false},
+		{"owner", RoleUser, false},  // 未知のロール
+		{RoleAdmin, "owner", false}, // 未知の権限は誰も満たさない
+	}
+	for _, tt := range tests {
+		u := &User{Role: tt.role}
+		if got := u.HasRole(tt.required); got != tt.want {
+			t.Errorf("User{Role: %q}.HasRole(%q) = %v, want %v", tt.role, tt.required, got, tt.want)
+		}
+	}
+}
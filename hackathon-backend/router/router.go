@@ -2,6 +2,7 @@ package router
 
 import (
 	"hackathon-backend/controller"
+	"hackathon-backend/model"
 	"net/http"
 )
 
@@ -19,10 +20,30 @@ func NewRouter(
 	productLikeCtrl *controller.ProductLikeController,
 	userUpdateCtrl *controller.UserUpdateController,
 	productDescCtrl *controller.ProductDescriptionController,
+	productPriceCtrl *controller.ProductPriceController,
+	reportCtrl *controller.ReportController,
+	adminCtrl *controller.AdminController,
+	moderationCtrl *controller.ModerationController,
+	aiUsageCtrl *controller.AIUsageController,
+	listingReviewCtrl *controller.ListingReviewController,
+	replySuggestionCtrl *controller.ReplySuggestionController,
+	similarProductCtrl *controller.SimilarProductController,
+	feedCtrl *controller.FeedController,
+	sellerAnalyticsCtrl *controller.SellerAnalyticsController,
+	followCtrl *controller.FollowController,
+	notificationCtrl *controller.NotificationController,
+	accountCtrl *controller.AccountController,
+	addressCtrl *controller.AddressController,
+	authMw *controller.AuthMiddleware,
 ) http.Handler {
 	mux := http.NewServeMux()
 
 	// --- ルーティング定義 ---
+	// 書き込み系 (出品・購入・メッセージ・いいね等) は authMw.RequireActive を通し、
+	// 利用停止中のユーザーを 403 にする。閲覧系はそのまま。
+	// AI系はさらに aiUsageCtrl.Limit で日次上限・同時実行数を確認し、使用量を記録する。
+	// 自動審査でAIを呼ぶ投稿 (出品・更新・メッセージ送信) は aiUsageCtrl.Moderated で
+	// 日次上限とは別に1分あたりの回数を制限し、審査の使用量を記録する。
 
 	// /users (GET: Search, POST: Register)
 	mux.HandleFunc("/users", func(w http.ResponseWriter, r *http.Request) {
@@ -32,6 +53,8 @@ func NewRouter(
 		}
 
 		switch r.Method {
+		case http.MethodGet:
+			searchUserCtrl.HandleSearch(w, r)
 		case http.MethodPost:
 			registerUserCtrl.Handle(w, r)
 		default:
@@ -48,7 +71,9 @@ func NewRouter(
 		case http.MethodGet:
 			searchUserCtrl.HandleGetMe(w, r)
 		case http.MethodPut: // ★追加: 更新はPUT
-			userUpdateCtrl.HandleUpdate(w, r)
+			authMw.RequireActive(userUpdateCtrl.HandleUpdate)(w, r)
+		case http.MethodDelete: // 退会 (利用停止・BAN中は usecase で断る)
+			accountCtrl.HandleDelete(w, r)
 		default:
 			w.WriteHeader(http.StatusMethodNotAllowed)
 		}
@@ -61,13 +86,13 @@ func NewRouter(
 		}
 		switch r.Method {
 		case http.MethodPost:
-			productRegisterCtrl.Handler(w, r)
+			authMw.RequireActive(aiUsageCtrl.Moderated("moderate_product", productRegisterCtrl.Handler))(w, r)
 		case http.MethodGet:
 			productSearchCtrl.HandleListProducts(w, r)
 		case http.MethodDelete:
-			productDeleteCtrl.HandleDeleteProduct(w, r)
+			authMw.RequireActive(productDeleteCtrl.HandleDeleteProduct)(w, r)
 		case http.MethodPut:
-			productUpdateCtrl.HandleUpdateProduct(w, r)
+			authMw.RequireActive(aiUsageCtrl.Moderated("moderate_product", productUpdateCtrl.HandleUpdateProduct))(w, r)
 		default:
 			w.WriteHeader(http.StatusMethodNotAllowed)
 		}
@@ -85,6 +110,42 @@ func NewRouter(
 		}
 	})
 
+	// 急上昇
+	mux.HandleFunc("/products/trending", func(w http.ResponseWriter, r *http.Request) {
+		if !enableCORS(w, r) {
+			return
+		}
+		if r.Method == http.MethodGet {
+			productSearchCtrl.HandleGetTrending(w, r)
+		} else {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+
+	// おすすめフィード
+	mux.HandleFunc("/products/feed", func(w http.ResponseWriter, r *http.Request) {
+		if !enableCORS(w, r) {
+			return
+		}
+		if r.Method == http.MethodGet {
+			feedCtrl.HandleGetFeed(w, r)
+		} else {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+
+	// 類似商品
+	mux.HandleFunc("/products/{id}/similar", func(w http.ResponseWriter, r *http.Request) {
+		if !enableCORS(w, r) {
+			return
+		}
+		if r.Method == http.MethodGet {
+			similarProductCtrl.HandleGetSimilar(w, r)
+		} else {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+
 	mux.HandleFunc("/users/{id}/products", func(w http.ResponseWriter, r *http.Request) {
 		if !enableCORS(w, r) {
 			return
@@ -94,6 +155,41 @@ func NewRouter(
 		}
 	})
 
+	// フォロー (POST: フォロー, DELETE: フォロー解除)
+	mux.HandleFunc("/users/{id}/follow", func(w http.ResponseWriter, r *http.Request) {
+		if !enableCORS(w, r) {
+			return
+		}
+		switch r.Method {
+		case http.MethodPost:
+			authMw.RequireActive(followCtrl.HandleFollow)(w, r)
+		case http.MethodDelete:
+			authMw.RequireActive(followCtrl.HandleUnfollow)(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	mux.HandleFunc("/users/{id}/followers", func(w http.ResponseWriter, r *http.Request) {
+		if !enableCORS(w, r) {
+			return
+		}
+		if r.Method == http.MethodGet {
+			followCtrl.HandleGetFollowers(w, r)
+		} else {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	mux.HandleFunc("/users/{id}/following", func(w http.ResponseWriter, r *http.Request) {
+		if !enableCORS(w, r) {
+			return
+		}
+		if r.Method == http.MethodGet {
+			followCtrl.HandleGetFollowing(w, r)
+		} else {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+
 	mux.HandleFunc("/users/{id}", func(w http.ResponseWriter, r *http.Request) {
 		if !enableCORS(w, r) {
 			return
@@ -109,7 +205,19 @@ func NewRouter(
 			return
 		}
 		if r.Method == http.MethodPost {
-			productPurchaseCtrl.HandlePurchaseProduct(w, r)
+			authMw.RequireActive(productPurchaseCtrl.HandlePurchaseProduct)(w, r)
+		} else {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+
+	// 売れた商品の届け先 (出品者のみ)
+	mux.HandleFunc("/products/{id}/shipping", func(w http.ResponseWriter, r *http.Request) {
+		if !enableCORS(w, r) {
+			return
+		}
+		if r.Method == http.MethodGet {
+			addressCtrl.HandleGetShipping(w, r)
 		} else {
 			w.WriteHeader(http.StatusMethodNotAllowed)
 		}
@@ -122,9 +230,15 @@ func NewRouter(
 		}
 		switch r.Method {
 		case http.MethodPost:
-			messageCtrl.HandleSendMessage(w, r)
+			authMw.RequireActive(aiUsageCtrl.Moderated("moderate_message", messageCtrl.HandleSendMessage))(w, r)
 		case http.MethodGet:
-			messageCtrl.HandleGetChat(w, r)
+			// 翻訳付き (?translate=true) はLLMを呼ぶので、AI系と同じ上限・記録を通す
+			// 閲覧なので利用停止中でも読める (RequireActive ではなく RequireAuth)
+			if r.URL.Query().Get("translate") == "true" {
+				authMw.RequireAuth(aiUsageCtrl.Limit("translate_messages", messageCtrl.HandleGetChat))(w, r)
+			} else {
+				messageCtrl.HandleGetChat(w, r)
+			}
 		default:
 			w.WriteHeader(http.StatusMethodNotAllowed)
 		}
@@ -143,6 +257,7 @@ func NewRouter(
 	})
 
 	// /messages/read
+	// 既読にするだけで他の人に見える内容は増えないので、利用停止中でも使える (RequireActive を通さない)
 	mux.HandleFunc("/messages/read", func(w http.ResponseWriter, r *http.Request) {
 		if !enableCORS(w, r) {
 			return
@@ -155,7 +270,7 @@ func NewRouter(
 	})
 
 	// いいね機能
-	// GET: 状態確認, POST: 切り替え
+	// GET: 状態確認, POST: 切り替え, PUT: いいね, DELETE: 解除
 	mux.HandleFunc("/products/{id}/like", func(w http.ResponseWriter, r *http.Request) {
 		if !enableCORS(w, r) {
 			return
@@ -164,7 +279,11 @@ func NewRouter(
 		case http.MethodGet:
 			productLikeCtrl.HandleGetLikeStatus(w, r)
 		case http.MethodPost:
-			productLikeCtrl.HandleToggleLike(w, r)
+			authMw.RequireActive(productLikeCtrl.HandleToggleLike)(w, r)
+		case http.MethodPut:
+			authMw.RequireActive(productLikeCtrl.HandleLike)(w, r)
+		case http.MethodDelete:
+			authMw.RequireActive(productLikeCtrl.HandleUnlike)(w, r)
 		default:
 			w.WriteHeader(http.StatusMethodNotAllowed)
 		}
@@ -179,6 +298,87 @@ func NewRouter(
 			productSearchCtrl.HandleGetSelling(w, r)
 		}
 	})
+	// 出品の分析 (閲覧・いいね・問い合わせ・成約)
+	mux.HandleFunc("/users/me/analytics", func(w http.ResponseWriter, r *http.Request) {
+		if !enableCORS(w, r) {
+			return
+		}
+		if r.Method == http.MethodGet {
+			sellerAnalyticsCtrl.HandleGetAnalytics(w, r)
+		} else {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	// 住所録 (GET: 一覧, POST: 登録)
+	mux.HandleFunc("/users/me/addresses", func(w http.ResponseWriter, r *http.Request) {
+		if !enableCORS(w, r) {
+			return
+		}
+		switch r.Method {
+		case http.MethodGet:
+			addressCtrl.HandleList(w, r)
+		case http.MethodPost:
+			authMw.RequireActive(addressCtrl.HandleCreate)(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	mux.HandleFunc("/users/me/addresses/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if !enableCORS(w, r) {
+			return
+		}
+		switch r.Method {
+		case http.MethodPut:
+			authMw.RequireActive(addressCtrl.HandleUpdate)(w, r)
+		case http.MethodDelete:
+			authMw.RequireActive(addressCtrl.HandleDelete)(w, r)
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	// 本人のデータのエクスポート
+	mux.HandleFunc("/users/me/export", func(w http.ResponseWriter, r *http.Request) {
+		if !enableCORS(w, r) {
+			return
+		}
+		if r.Method == http.MethodGet {
+			accountCtrl.HandleExport(w, r)
+		} else {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	// フォロー中の出品者の新着
+	mux.HandleFunc("/users/me/following/products", func(w http.ResponseWriter, r *http.Request) {
+		if !enableCORS(w, r) {
+			return
+		}
+		if r.Method == http.MethodGet {
+			followCtrl.HandleGetFollowingProducts(w, r)
+		} else {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	// お知らせ
+	mux.HandleFunc("/users/me/notifications", func(w http.ResponseWriter, r *http.Request) {
+		if !enableCORS(w, r) {
+			return
+		}
+		if r.Method == http.MethodGet {
+			notificationCtrl.HandleGetNotifications(w, r)
+		} else {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+	mux.HandleFunc("/users/me/notifications/read", func(w http.ResponseWriter, r *http.Request) {
+		if !enableCORS(w, r) {
+			return
+		}
+		if r.Method == http.MethodPost {
+			notificationCtrl.HandleMarkAllRead(w, r)
+		} else {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
 	// 購入した商品
 	mux.HandleFunc("/users/me/purchases", func(w http.ResponseWriter, r *http.Request) {
 		if !enableCORS(w, r) {
@@ -204,7 +404,19 @@ func NewRouter(
 			return
 		}
 		if r.Method == http.MethodPost {
-			productDescCtrl.HandleGenerate(w, r)
+			authMw.RequireActive(aiUsageCtrl.Limit("generate_description", productDescCtrl.HandleGenerate))(w, r)
+		} else {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+
+	// AI生成 (ストリーミング)
+	mux.HandleFunc("/products/generate-description/stream", func(w http.ResponseWriter, r *http.Request) {
+		if !enableCORS(w, r) {
+			return
+		}
+		if r.Method == http.MethodPost {
+			authMw.RequireActive(aiUsageCtrl.Limit("generate_description_stream", productDescCtrl.HandleGenerateStream))(w, r)
 		} else {
 			w.WriteHeader(http.StatusMethodNotAllowed)
 		}
@@ -216,7 +428,54 @@ func NewRouter(
 			return
 		}
 		if r.Method == http.MethodPost {
-			productDescCtrl.HandleGenerateFromImage(w, r)
+			authMw.RequireActive(aiUsageCtrl.Limit("generate_from_image", productDescCtrl.HandleGenerateFromImage))(w, r)
+		} else {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+
+	// 価格の目安 (売れた類似商品から計算)
+	mux.HandleFunc("/products/suggest-price", func(w http.ResponseWriter, r *http.Request) {
+		if !enableCORS(w, r) {
+			return
+		}
+		if r.Method == http.MethodPost {
+			authMw.RequireActive(aiUsageCtrl.Limit("suggest_price", productPriceCtrl.HandleSuggestPrice))(w, r)
+		} else {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+
+	// 出品内容の採点 (出品済み / 出品前)
+	mux.HandleFunc("/products/{id}/review-listing", func(w http.ResponseWriter, r *http.Request) {
+		if !enableCORS(w, r) {
+			return
+		}
+		if r.Method == http.MethodPost {
+			authMw.RequireActive(aiUsageCtrl.Limit("review_listing", listingReviewCtrl.HandleReviewProduct))(w, r)
+		} else {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+
+	mux.HandleFunc("/products/review-listing", func(w http.ResponseWriter, r *http.Request) {
+		if !enableCORS(w, r) {
+			return
+		}
+		if r.Method == http.MethodPost {
+			authMw.RequireActive(aiUsageCtrl.Limit("review_listing_draft", listingReviewCtrl.HandleReviewDraft))(w, r)
+		} else {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+
+	// 返信候補 (送信はしない)
+	mux.HandleFunc("/conversations/{partner}/suggested-replies", func(w http.ResponseWriter, r *http.Request) {
+		if !enableCORS(w, r) {
+			return
+		}
+		if r.Method == http.MethodGet {
+			authMw.RequireActive(aiUsageCtrl.Limit("suggested_replies", replySuggestionCtrl.HandleSuggestReplies))(w, r)
 		} else {
 			w.WriteHeader(http.StatusMethodNotAllowed)
 		}
@@ -227,7 +486,7 @@ func NewRouter(
 			return
 		}
 		if r.Method == http.MethodPut {
-			messageCtrl.HandleUnsendMessage(w, r)
+			authMw.RequireActive(messageCtrl.HandleUnsendMessage)(w, r)
 		} else {
 			w.WriteHeader(http.StatusMethodNotAllowed)
 		}
@@ -238,12 +497,56 @@ func NewRouter(
 			return
 		}
 		if r.Method == http.MethodDelete {
-			messageCtrl.HandleDeleteMessage(w, r)
+			authMw.RequireActive(messageCtrl.HandleDeleteMessage)(w, r)
+		} else {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	})
+
+	// 通報 (利用停止中のユーザーによる通報の乱発を防ぐため RequireActive を通す)
+	mux.HandleFunc("/reports", func(w http.ResponseWriter, r *http.Request) {
+		if !enableCORS(w, r) {
+			return
+		}
+		if r.Method == http.MethodPost {
+			authMw.RequireActive(reportCtrl.HandleCreateReport)(w, r)
 		} else {
 			w.WriteHeader(http.StatusMethodNotAllowed)
 		}
 	})
 
+	// --- 管理者用 (/admin) ---
+	// moderator 以上: 利用停止・出品の取り下げ・取引キャンセル・チャット閲覧・自動審査結果の確認
+	// admin のみ: BAN・ロール変更・監査ログ閲覧・AI利用額の確認
+	adminRoute := func(pattern, method, role string, handler http.HandlerFunc) {
+		guarded := authMw.RequireRole(role, handler)
+		mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+			if !enableCORS(w, r) {
+				return
+			}
+			if r.Method == method {
+				guarded(w, r)
+			} else {
+				w.WriteHeader(http.StatusMethodNotAllowed)
+			}
+		})
+	}
+	adminRoute("/admin/users/{id}/suspend", http.MethodPost, model.RoleModerator, adminCtrl.HandleSuspendUser)
+	adminRoute("/admin/users/{id}/ban", http.MethodPost, model.RoleAdmin, adminCtrl.HandleBanUser)
+	adminRoute("/admin/users/{id}/unsuspend", http.MethodPost, model.RoleModerator, adminCtrl.HandleUnsuspendUser)
+	adminRoute("/admin/users/{id}/role", http.MethodPut, model.RoleAdmin, adminCtrl.HandleChangeRole)
+	adminRoute("/admin/products/{id}/takedown", http.MethodPost, model.RoleModerator, adminCtrl.HandleTakeDownProduct)
+	adminRoute("/admin/products/{id}/restore", http.MethodPost, model.RoleModerator, adminCtrl.HandleRestoreProduct)
+	adminRoute("/admin/products/{id}/cancel-purchase", http.MethodPost, model.RoleModerator, adminCtrl.HandleCancelPurchase)
+	adminRoute("/admin/messages", http.MethodGet, model.RoleModerator, adminCtrl.HandleViewChat)
+	adminRoute("/admin/moderation", http.MethodGet, model.RoleModerator, moderationCtrl.HandleListResults)
+	adminRoute("/admin/audit-logs", http.MethodGet, model.RoleAdmin, adminCtrl.HandleListAuditLogs)
+	adminRoute("/admin/ai-usage", http.MethodGet, model.RoleAdmin, aiUsageCtrl.HandleSpendReport)
+
+	// モデレーター用: 通報一覧・通報の確認と対応
+	adminRoute("/moderation/reports", http.MethodGet, model.RoleModerator, reportCtrl.HandleListReports)
+	adminRoute("/moderation/reports/{id}", http.MethodPut, model.RoleModerator, reportCtrl.HandleUpdateReport)
+
 	return mux
 }
 
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache: 生成結果などを一定時間保存しておくキャッシュ
+// 今はプロセス内の MemoryCache のみ。インスタンスをまたいで共有したくなったら Redis などで実装してください
+type Cache interface {
+	// Get: 期限内の値があれば返します
+	Get(key string) ([]byte, bool)
+	// Set: ttl の間だけ値を保存します
+	Set(key string, value []byte, ttl time.Duration)
+	// Delete: 値を削除します
+	Delete(key string)
+}
+
+type memoryCacheEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// MemoryCache: プロセス内のメモリに保存するキャッシュ
+// maxEntries を超えたら期限切れのものから、それでも足りなければ期限の近いものから捨てます
+type MemoryCache struct {
+	mu         sync.Mutex
+	entries    map[string]memoryCacheEntry
+	maxEntries int
+}
+
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{
+		entries:    make(map[string]memoryCacheEntry),
+		maxEntries: maxEntries,
+	}
+}
+
+func (c *MemoryCache) Get(key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(e.expiresAt) {
+		delete(c.entries, key)
+		return nil, false
+	}
+	return e.value, true
+}
+
+func (c *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.entries[key]; !exists && len(c.entries) >= c.maxEntries {
+		c.evict()
+	}
+	c.entries[key] = memoryCacheEntry{value: value, expiresAt: time.Now().Add(ttl)}
+}
+
+func (c *MemoryCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, key)
+}
+
+// 空きを作る (呼び出し側でロック済みであること)
+func (c *MemoryCache) evict() {
+	now := time.Now()
+	for k, e := range c.entries {
+		if now.After(e.expiresAt) {
+			delete(c.entries, k)
+		}
+	}
+	if len(c.entries) < c.maxEntries {
+		return
+	}
+
+	var oldestKey string
+	var oldest time.Time
+	for k, e := range c.entries {
+		if oldestKey == "" || e.expiresAt.Before(oldest) {
+			oldestKey, oldest = k, e.expiresAt
+		}
+	}
+	delete(c.entries, oldestKey)
+}
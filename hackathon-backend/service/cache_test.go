@@ -0,0 +1,81 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestMemoryCache(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(c *MemoryCache) (string, bool)
+		want string
+		ok   bool
+	}{
+		{"miss", func(c *MemoryCache) (string, bool) {
+			return get(c, "a")
+		}, "", false},
+		{"hit", func(c *MemoryCache) (string, bool) {
+			c.Set("a", []byte("1"), time.Minute)
+			return get(c, "a")
+		}, "1", true},
+		{"overwrite", func(c *MemoryCache) (string, bool) {
+			c.Set("a", []byte("1"), time.Minute)
+			c.Set("a", []byte("2"), time.Minute)
+			return get(c, "a")
+		}, "2", true},
+		{"expired", func(c *MemoryCache) (string, bool) {
+			c.Set("a", []byte("1"), -time.Second)
+			return get(c, "a")
+		}, "", false},
+		{"deleted", func(c *MemoryCache) (string, bool) {
+			c.Set("a", []byte("1"), time.Minute)
+			c.Delete("a")
+			return get(c, "a")
+		}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.run(NewMemoryCache(10))
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("got (%q, %v), want (%q, %v)", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestMemoryCacheEviction(t *testing.T) {
+	tests := []struct {
+		name     string
+		ttls     []time.Duration // key0, key1, ... の順に保存する
+		wantGone string          // 上限を超えたときに捨てられるキー
+	}{
+		{"expired entries go first", []time.Duration{time.Hour, -time.Second, time.Minute}, "key1"},
+		{"then the one expiring soonest", []time.Duration{time.Hour, time.Minute, 2 * time.Minute}, "key1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewMemoryCache(len(tt.ttls))
+			for i, ttl := range tt.ttls {
+				c.Set(fmt.Sprintf("key%d", i), []byte("v"), ttl)
+			}
+			c.Set("new", []byte("v"), time.Hour)
+
+			if len(c.entries) > len(tt.ttls) {
+				t.Fatalf("cache holds %d entries, max %d", len(c.entries), len(tt.ttls))
+			}
+			if _, ok := c.entries[tt.wantGone]; ok {
+				t.Errorf("%s was not evicted", tt.wantGone)
+			}
+			if _, ok := c.Get("new"); !ok {
+				t.Errorf("new entry was not stored")
+			}
+		})
+	}
+}
+
+func get(c *MemoryCache, key string) (string, bool) {
+	b, ok := c.Get(key)
+	return string(b), ok
+}
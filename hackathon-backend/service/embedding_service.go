@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	aiplatform "cloud.google.com/go/aiplatform/apiv1"
+	"cloud.google.com/go/aiplatform/apiv1/aiplatformpb"
+	"google.golang.org/api/option"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// EmbeddingService: 文章をベクトルに変換する (類似商品の検索用)
+// 実装: VertexEmbeddingService, OpenAIService, FakeLLMService
+type EmbeddingService interface {
+	// EmbedText: 文章をベクトルにします
+	EmbedText(ctx context.Context, text string) ([]float32, error)
+	// EmbeddingModel: 使っているモデル名 (モデルが変わったら保存済みのベクトルは使えない)
+	EmbeddingModel() string
+}
+
+// VertexEmbeddingService: Vertex AI のテキスト埋め込みモデルを呼ぶ実装
+type VertexEmbeddingService struct {
+	client    *aiplatform.PredictionClient
+	endpoint  string
+	modelName string
+}
+
+func NewVertexEmbeddingService(ctx context.Context, projectID, location, modelName string) (*VertexEmbeddingService, error) {
+	client, err := aiplatform.NewPredictionClient(ctx, option.WithEndpoint(location+"-aiplatform.googleapis.com:443"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create prediction client: %w", err)
+	}
+	return &VertexEmbeddingService{
+		client:    client,
+		endpoint:  fmt.Sprintf("projects/%s/locations/%s/publishers/google/models/%s", projectID, location, modelName),
+		modelName: modelName, // "text-multilingual-embedding-002"
+	}, nil
+}
+
+func (s *VertexEmbeddingService) EmbedText(ctx context.Context, text string) ([]float32, error) {
+	instance, err := structpb.NewValue(map[string]interface{}{
+		"content":   text,
+		"task_type": "SEMANTIC_SIMILARITY",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Predict(ctx, &aiplatformpb.PredictRequest{
+		Endpoint:  s.endpoint,
+		Instances: []*structpb.Value{instance},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed text: %w", err)
+	}
+	if len(resp.Predictions) == 0 {
+		return nil, fmt.Errorf("no embedding returned")
+	}
+
+	// predictions[0].embeddings.values (トークン数は embeddings.statistics.token_count)
+	embeddings := resp.Predictions[0].GetStructValue().GetFields()["embeddings"].GetStructValue().GetFields()
+	tokens := embeddings["statistics"].GetStructValue().GetFields()["token_count"].GetNumberValue()
+	recordUsage(ctx, s.modelName, int(tokens), 0)
+
+	values := embeddings["values"].GetListValue().GetValues()
+	if len(values) == 0 {
+		return nil, fmt.Errorf("embedding has no values")
+	}
+	vec := make([]float32, len(values))
+	for i, v := range values {
+		vec[i] = float32(v.GetNumberValue())
+	}
+	return vec, nil
+}
+
+func (s *VertexEmbeddingService) EmbeddingModel() string {
+	return s.modelName
+}
+
+// Close: アプリ終了時にクライアントを閉じます
+func (s *VertexEmbeddingService) Close() {
+	if s.client != nil {
+		s.client.Close()
+	}
+}
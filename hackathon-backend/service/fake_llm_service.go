@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash/fnv"
+	"strings"
+	"unicode/utf8"
+)
+
+// FakeRule: プロンプトに Contains が含まれていたら Response を返す
+type FakeRule struct {
+	Contains string
+	Response string
+}
+
+// FakeLLMService: 外部APIを呼ばない決定的なLLM実装 (ローカル開発・オフライン用)
+// 同じ入力には常に同じ出力を返します
+type FakeLLMService struct {
+	Rules []FakeRule
+}
+
+// NewFakeLLMService: このアプリのプロンプトにそれらしい応答を返すルール付きで作成します
+func NewFakeLLMService() *FakeLLMService {
+	return &FakeLLMService{
+		Rules: []FakeRule{
+			{
+				Contains: "コンテンツ審査",
+				Response: `{"decision": "allow", "categories": [], "reasons": []}`,
+			},
+			{
+				Contains: "説明文の案を",
+				Response: `{"drafts": ["テスト用に生成された説明文の案その1です。", "テスト用に生成された説明文の案その2です。", "テスト用に生成された説明文の案その3です。"]}`,
+			},
+			{
+				Contains: "返信の候補を",
+				Response: `{"replies": ["お問い合わせありがとうございます。まだ購入いただけます。", "確認してご連絡しますので、少々お待ちください。", "申し訳ありませんが、お値下げは難しいです。"]}`,
+			},
+			{
+				Contains: "商品画像を解析",
+				Response: `{"name": "テスト商品", "price": 3000, "keywords": ["テスト", "サンプル"], "description": "オフライン環境で生成されたテスト用の商品説明です。"}`,
+			},
+		},
+	}
+}
+
+// GenerateText: ルールに一致すればその応答を、なければプロンプトから決まるダミー文章を返します
+func (s *FakeLLMService) GenerateText(ctx context.Context, promptText string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	for _, rule := range s.Rules {
+		if strings.Contains(promptText, rule.Contains) {
+			recordFakeUsage(ctx, promptText, rule.Response)
+			return rule.Response, nil
+		}
+	}
+	text := "これはテスト用に生成された文章です。(" + shortHash([]byte(promptText)) + ")"
+	recordFakeUsage(ctx, promptText, text)
+	return text, nil
+}
+
+// GenerateTextStream: GenerateText と同じ文章を数文字ずつ onChunk へ渡します
+func (s *FakeLLMService) GenerateTextStream(ctx context.Context, promptText string, onChunk func(chunk string) error) error {
+	text, err := s.GenerateText(ctx, promptText)
+	if err != nil {
+		return err
+	}
+	runes := []rune(text)
+	for i := 0; i < len(runes); i += 8 {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		end := min(i+8, len(runes))
+		if err := onChunk(string(runes[i:end])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GenerateFromImage: 画像の内容は見ずに GenerateText と同じ規則で返します
+func (s *FakeLLMService) GenerateFromImage(ctx context.Context, promptText string, imgData []byte, mimeType string) (string, error) {
+	return s.GenerateText(ctx, promptText)
+}
+
+// GenerateJSON: ルールに一致すればその応答を、なければスキーマから組み立てたサンプルを返します
+func (s *FakeLLMService) GenerateJSON(ctx context.Context, promptText string, schema *JSONSchema, imgData []byte, mimeType string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	for _, rule := range s.Rules {
+		if strings.Contains(promptText, rule.Contains) {
+			recordFakeUsage(ctx, promptText, rule.Response)
+			return rule.Response, nil
+		}
+	}
+	b, err := json.Marshal(sampleFromSchema(schema))
+	if err != nil {
+		return "", err
+	}
+	recordFakeUsage(ctx, promptText, string(b))
+	return string(b), nil
+}
+
+// 偽の埋め込みベクトルの次元数
+const fakeEmbeddingDims = 256
+
+// EmbedText: 文字の2-gramをハッシュで次元に割り振ったベクトルを返します
+// 文字が似ている文章ほど近いベクトルになります
+func (s *FakeLLMService) EmbedText(ctx context.Context, text string) ([]float32, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	vec := make([]float32, fakeEmbeddingDims)
+	runes := []rune(strings.ToLower(text))
+	for i := 0; i+1 < len(runes); i++ {
+		h := fnv.New32a()
+		h.Write([]byte(string(runes[i : i+2])))
+		vec[h.Sum32()%fakeEmbeddingDims]++
+	}
+	recordFakeUsage(ctx, text, "")
+	return vec, nil
+}
+
+func (s *FakeLLMService) EmbeddingModel() string {
+	return "fake-embedding"
+}
+
+func (s *FakeLLMService) Close() {}
+
+// スキーマの型に合わせたダミー値を作る
+func sampleFromSchema(schema *JSONSchema) interface{} {
+	if schema == nil {
+		return nil
+	}
+	if len(schema.Enum) > 0 {
+		return schema.Enum[0]
+	}
+	switch schema.Type {
+	case "object":
+		obj := make(map[string]interface{}, len(schema.Properties))
+		for k, v := range schema.Properties {
+			obj[k] = sampleFromSchema(v)
+		}
+		return obj
+	case "array":
+		return []interface{}{sampleFromSchema(schema.Items)}
+	case "integer":
+		return 1000
+	case "number":
+		return 1.0
+	case "boolean":
+		return false
+	}
+	return "テスト"
+}
+
+// 文字数をトークン数の代わりに記録する
+func recordFakeUsage(ctx context.Context, promptText, output string) {
+	recordUsage(ctx, "fake", utf8.RuneCountInString(promptText), utf8.RuneCountInString(output))
+}
+
+func shortHash(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:4])
+}
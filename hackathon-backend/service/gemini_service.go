@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"cloud.google.com/go/vertexai/genai"
+	"google.golang.org/api/iterator"
 )
 
 type GeminiService struct {
@@ -30,8 +31,8 @@ func NewGeminiService(ctx context.Context, projectID, location, modelName string
 	}, nil
 }
 
-// GenerateDescription: プロンプトを送信して生成結果を返します
-func (s *GeminiService) GenerateDescription(ctx context.Context, promptText string) (string, error) {
+// GenerateText: プロンプトを送信して生成結果を返します
+func (s *GeminiService) GenerateText(ctx context.Context, promptText string) (string, error) {
 	model := s.client.GenerativeModel(s.modelName)
 
 	// パラメータ調整 (必要に応じて変更)
@@ -41,6 +42,7 @@ func (s *GeminiService) GenerateDescription(ctx context.Context, promptText stri
 	if err != nil {
 		return "", fmt.Errorf("failed to generate content: %w", err)
 	}
+	s.recordUsage(ctx, resp.UsageMetadata)
 
 	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
 		return "", fmt.Errorf("no content generated")
@@ -57,6 +59,40 @@ func (s *GeminiService) GenerateDescription(ctx context.Context, promptText stri
 	return sb.String(), nil
 }
 
+// GenerateTextStream: ストリーミングAPIで生成し、届いたテキストを順に onChunk へ渡します
+func (s *GeminiService) GenerateTextStream(ctx context.Context, promptText string, onChunk func(chunk string) error) error {
+	model := s.client.GenerativeModel(s.modelName)
+	model.SetTemperature(0.7)
+
+	// 使用量は最後に届いたチャンクのものが合計になる (途中で打ち切っても記録する)
+	var usage *genai.UsageMetadata
+	defer func() { s.recordUsage(ctx, usage) }()
+
+	iter := model.GenerateContentStream(ctx, genai.Text(promptText))
+	for {
+		resp, err := iter.Next()
+		if err == iterator.Done {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to stream content: %w", err)
+		}
+		if resp.UsageMetadata != nil {
+			usage = resp.UsageMetadata
+		}
+		if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil {
+			continue
+		}
+		for _, part := range resp.Candidates[0].Content.Parts {
+			if txt, ok := part.(genai.Text); ok && txt != "" {
+				if err := onChunk(string(txt)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+}
+
 // 画像を渡して生成
 func (s *GeminiService) GenerateFromImage(ctx context.Context, promptText string, imgData []byte, mimeType string) (string, error) {
 	model := s.client.GenerativeModel(s.modelName)
@@ -70,6 +106,7 @@ func (s *GeminiService) GenerateFromImage(ctx context.Context, promptText string
 	if err != nil {
 		return "", fmt.Errorf("failed to generate from image: %w", err)
 	}
+	s.recordUsage(ctx, resp.UsageMetadata)
 
 	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
 		return "", fmt.Errorf("no content generated")
@@ -85,6 +122,47 @@ func (s *GeminiService) GenerateFromImage(ctx context.Context, promptText string
 	return sb.String(), nil
 }
 
+// GenerateJSON: 構造化出力モード (responseSchema) でJSONを生成します
+func (s *GeminiService) GenerateJSON(ctx context.Context, promptText string, schema *JSONSchema, imgData []byte, mimeType string) (string, error) {
+	model := s.client.GenerativeModel(s.modelName)
+	model.SetTemperature(0.2) // 形式を守らせたいので低め
+	model.ResponseMIMEType = "application/json"
+	model.ResponseSchema = schema.toGenai()
+
+	parts := []genai.Part{genai.Text(promptText)}
+	if len(imgData) > 0 {
+		parts = []genai.Part{genai.ImageData(mimeType, imgData), genai.Text(promptText)}
+	}
+
+	resp, err := model.GenerateContent(ctx, parts...)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate json: %w", err)
+	}
+	s.recordUsage(ctx, resp.UsageMetadata)
+
+	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
+		return "", fmt.Errorf("no content generated")
+	}
+
+	var sb strings.Builder
+	for _, part := range resp.Candidates[0].Content.Parts {
+		if txt, ok := part.(genai.Text); ok {
+			sb.WriteString(string(txt))
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// 呼び出し元のコンテキストに使用量を記録する (思考トークンも出力側として課金されるので含める)
+func (s *GeminiService) recordUsage(ctx context.Context, meta *genai.UsageMetadata) {
+	if meta == nil {
+		recordUsage(ctx, s.modelName, 0, 0)
+		return
+	}
+	recordUsage(ctx, s.modelName, int(meta.PromptTokenCount), int(meta.CandidatesTokenCount+meta.ThoughtsTokenCount))
+}
+
 // Close: アプリ終了時にクライアントを閉じます
 func (s *GeminiService) Close() {
 	if s.client != nil {
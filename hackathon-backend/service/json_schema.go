@@ -0,0 +1,48 @@
+package service
+
+import "cloud.google.com/go/vertexai/genai"
+
+// JSONSchema: 構造化出力 (JSONモード) で使う出力スキーマ
+// 各LLM実装がそれぞれのAPIの形式に変換して使います
+type JSONSchema struct {
+	Type        string                 `json:"type"` // object / array / string / integer / number / boolean
+	Description string                 `json:"description,omitempty"`
+	Properties  map[string]*JSONSchema `json:"properties,omitempty"`
+	Required    []string               `json:"required,omitempty"`
+	Items       *JSONSchema            `json:"items,omitempty"`
+	Enum        []string               `json:"enum,omitempty"`
+}
+
+// toGenai: Vertex AI の Schema に変換
+func (s *JSONSchema) toGenai() *genai.Schema {
+	if s == nil {
+		return nil
+	}
+	gs := &genai.Schema{
+		Description: s.Description,
+		Required:    s.Required,
+		Items:       s.Items.toGenai(),
+		Enum:        s.Enum,
+	}
+	switch s.Type {
+	case "object":
+		gs.Type = genai.TypeObject
+	case "array":
+		gs.Type = genai.TypeArray
+	case "string":
+		gs.Type = genai.TypeString
+	case "integer":
+		gs.Type = genai.TypeInteger
+	case "number":
+		gs.Type = genai.TypeNumber
+	case "boolean":
+		gs.Type = genai.TypeBoolean
+	}
+	if len(s.Properties) > 0 {
+		gs.Properties = make(map[string]*genai.Schema, len(s.Properties))
+		for k, v := range s.Properties {
+			gs.Properties[k] = v.toGenai()
+		}
+	}
+	return gs
+}
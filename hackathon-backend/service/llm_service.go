@@ -0,0 +1,20 @@
+package service
+
+import "context"
+
+// LLMService: テキスト・画像からの文章生成を行うLLMの共通インターフェース
+// 実装: GeminiService (Vertex AI), OpenAIService (OpenAI互換API), FakeLLMService (オフライン用)
+type LLMService interface {
+	// GenerateText: プロンプトを送信して生成結果を返します
+	GenerateText(ctx context.Context, promptText string) (string, error)
+	// GenerateTextStream: 生成されたテキストを届いた順に onChunk へ渡します
+	// ctx がキャンセルされるか onChunk がエラーを返すと生成を打ち切ります
+	GenerateTextStream(ctx context.Context, promptText string, onChunk func(chunk string) error) error
+	// GenerateFromImage: 画像とプロンプトを送信して生成結果を返します
+	GenerateFromImage(ctx context.Context, promptText string, imgData []byte, mimeType string) (string, error)
+	// GenerateJSON: schema に沿ったJSONを返すよう構造化出力モードで生成します
+	// 画像がない場合は imgData を nil で渡します
+	GenerateJSON(ctx context.Context, promptText string, schema *JSONSchema, imgData []byte, mimeType string) (string, error)
+	// Close: アプリ終了時に呼びます
+	Close()
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OpenAIService: OpenAI互換の Chat Completions API を呼ぶ実装
+// baseURL を変えれば Ollama や LM Studio などのローカルサーバーにも向けられます
+// 例: http://localhost:11434/v1
+type OpenAIService struct {
+	httpClient     *http.Client
+	baseURL        string
+	apiKey         string
+	modelName      string
+	embeddingModel string
+}
+
+func NewOpenAIService(baseURL, apiKey, modelName, embeddingModel string) *OpenAIService {
+	return &OpenAIService{
+		httpClient:     &http.Client{Timeout: 120 * time.Second},
+		baseURL:        strings.TrimSuffix(baseURL, "/"),
+		apiKey:         apiKey,
+		modelName:      modelName,
+		embeddingModel: embeddingModel,
+	}
+}
+
+type openAIChatReq struct {
+	Model          string                `json:"model"`
+	Messages       []openAIMessage       `json:"messages"`
+	Temperature    float64               `json:"temperature"`
+	ResponseFormat *openAIResponseFormat `json:"response_format,omitempty"`
+	Stream         bool                  `json:"stream,omitempty"`
+	StreamOptions  *openAIStreamOptions  `json:"stream_options,omitempty"`
+}
+
+type openAIStreamOptions struct {
+	IncludeUsage bool `json:"include_usage"` // 最後のチャンクで使用量を返させる
+}
+
+type openAIUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+}
+
+type openAIResponseFormat struct {
+	Type       string            `json:"type"` // "json_schema"
+	JSONSchema *openAIJSONSchema `json:"json_schema,omitempty"`
+}
+
+type openAIJSONSchema struct {
+	Name   string      `json:"name"`
+	Schema *JSONSchema `json:"schema"`
+}
+
+type openAIMessage struct {
+	Role    string      `json:"role"`
+	Content interface{} `json:"content"` // string または []openAIContentPart
+}
+
+type openAIContentPart struct {
+	Type     string          `json:"type"`
+	Text     string          `json:"text,omitempty"`
+	ImageURL *openAIImageURL `json:"image_url,omitempty"`
+}
+
+type openAIImageURL struct {
+	URL string `json:"url"`
+}
+
+type openAIChatRes struct {
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+	} `json:"choices"`
+	Usage *openAIUsage `json:"usage"`
+}
+
+// ストリーミング時に "data: " 行で届くチャンク
+type openAIChatChunk struct {
+	Choices []struct {
+		Delta struct {
+			Content string `json:"content"`
+		} `json:"delta"`
+	} `json:"choices"`
+	Usage *openAIUsage `json:"usage"`
+}
+
+// GenerateText: プロンプトを送信して生成結果を返します
+func (s *OpenAIService) GenerateText(ctx context.Context, promptText string) (string, error) {
+	return s.chat(ctx, 0.7, promptText, nil)
+}
+
+// GenerateTextStream: stream: true で送信し、SSE で届く差分を順に onChunk へ渡します
+func (s *OpenAIService) GenerateTextStream(ctx context.Context, promptText string, onChunk func(chunk string) error) error {
+	resp, err := s.post(ctx, openAIChatReq{
+		Model:         s.modelName,
+		Messages:      []openAIMessage{{Role: "user", Content: promptText}},
+		Temperature:   0.7,
+		Stream:        true,
+		StreamOptions: &openAIStreamOptions{IncludeUsage: true},
+	})
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	var usage *openAIUsage
+	defer func() { s.recordUsage(ctx, usage) }()
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data: ")
+		if !ok {
+			continue
+		}
+		if data == "[DONE]" {
+			return nil
+		}
+		var chunk openAIChatChunk
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return fmt.Errorf("failed to decode chat completions chunk: %w", err)
+		}
+		if chunk.Usage != nil {
+			usage = chunk.Usage
+		}
+		if len(chunk.Choices) == 0 || chunk.Choices[0].Delta.Content == "" {
+			continue
+		}
+		if err := onChunk(chunk.Choices[0].Delta.Content); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read chat completions stream: %w", err)
+	}
+	return nil
+}
+
+// GenerateFromImage: 画像は data URL にして送ります
+func (s *OpenAIService) GenerateFromImage(ctx context.Context, promptText string, imgData []byte, mimeType string) (string, error) {
+	return s.chat(ctx, 0.5, imageContent(promptText, imgData, mimeType), nil)
+}
+
+// GenerateJSON: response_format の json_schema で構造化出力させます
+func (s *OpenAIService) GenerateJSON(ctx context.Context, promptText string, schema *JSONSchema, imgData []byte, mimeType string) (string, error) {
+	var content interface{} = promptText
+	if len(imgData) > 0 {
+		content = imageContent(promptText, imgData, mimeType)
+	}
+	format := &openAIResponseFormat{
+		Type:       "json_schema",
+		JSONSchema: &openAIJSONSchema{Name: "response", Schema: schema},
+	}
+	return s.chat(ctx, 0.2, content, format)
+}
+
+func imageContent(promptText string, imgData []byte, mimeType string) []openAIContentPart {
+	dataURL := "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(imgData)
+	return []openAIContentPart{
+		{Type: "image_url", ImageURL: &openAIImageURL{URL: dataURL}},
+		{Type: "text", Text: promptText},
+	}
+}
+
+func (s *OpenAIService) chat(ctx context.Context, temperature float64, content interface{}, format *openAIResponseFormat) (string, error) {
+	resp, err := s.post(ctx, openAIChatReq{
+		Model:          s.modelName,
+		Messages:       []openAIMessage{{Role: "user", Content: content}},
+		Temperature:    temperature,
+		ResponseFormat: format,
+	})
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var res openAIChatRes
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return "", fmt.Errorf("failed to decode chat completions response: %w", err)
+	}
+	s.recordUsage(ctx, res.Usage)
+	if len(res.Choices) == 0 || res.Choices[0].Message.Content == "" {
+		return "", fmt.Errorf("no content generated")
+	}
+	return res.Choices[0].Message.Content, nil
+}
+
+type openAIEmbeddingReq struct {
+	Model string `json:"model"`
+	Input string `json:"input"`
+}
+
+type openAIEmbeddingRes struct {
+	Data []struct {
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+	Usage *openAIUsage `json:"usage"`
+}
+
+// EmbedText: /embeddings で文章をベクトルにします
+func (s *OpenAIService) EmbedText(ctx context.Context, text string) ([]float32, error) {
+	body, err := json.Marshal(openAIEmbeddingReq{Model: s.embeddingModel, Input: text})
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.send(ctx, "/embeddings", body)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var res openAIEmbeddingRes
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		return nil, fmt.Errorf("failed to decode embeddings response: %w", err)
+	}
+	// 埋め込みは出力トークンがないので入力だけ記録する
+	promptTokens := 0
+	if res.Usage != nil {
+		promptTokens = res.Usage.PromptTokens
+	}
+	recordUsage(ctx, s.embeddingModel, promptTokens, 0)
+
+	if len(res.Data) == 0 || len(res.Data[0].Embedding) == 0 {
+		return nil, fmt.Errorf("no embedding returned")
+	}
+	return res.Data[0].Embedding, nil
+}
+
+func (s *OpenAIService) EmbeddingModel() string {
+	return s.embeddingModel
+}
+
+// /chat/completions に送信する (200 以外はエラー。成功時の Body は呼び出し側で閉じる)
+func (s *OpenAIService) post(ctx context.Context, chatReq openAIChatReq) (*http.Response, error) {
+	body, err := json.Marshal(chatReq)
+	if err != nil {
+		return nil, err
+	}
+	return s.send(ctx, "/chat/completions", body)
+}
+
+// APIにJSONを送信する (200 以外はエラー。成功時の Body は呼び出し側で閉じる)
+func (s *OpenAIService) send(ctx context.Context, path string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if s.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call %s: %w", path, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer resp.Body.Close()
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("%s returned %d: %s", path, resp.StatusCode, string(msg))
+	}
+	return resp, nil
+}
+
+// 呼び出し元のコンテキストに使用量を記録する (usage を返さないサーバーでは 0 になる)
+func (s *OpenAIService) recordUsage(ctx context.Context, usage *openAIUsage) {
+	if usage == nil {
+		recordUsage(ctx, s.modelName, 0, 0)
+		return
+	}
+	recordUsage(ctx, s.modelName, usage.PromptTokens, usage.CompletionTokens)
+}
+
+func (s *OpenAIService) Close() {}
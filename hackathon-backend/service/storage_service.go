@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"strings"
@@ -55,3 +56,50 @@ func (s *StorageService) GenerateSignedURL(filename string) (string, error) {
 	url := fmt.Sprintf("https://storage.googleapis.com/%s/%s", s.bucketName, filename)
 	return url, nil
 }
+
+// DeleteImage: 保存済みの画像を削除します (UploadImage が返したURLでも、ファイル名だけでも可)
+// 既に無い画像や、このバケットの外の画像は何もしません
+func (s *StorageService) DeleteImage(ctx context.Context, imageURL string) error {
+	objectName, ok := s.objectName(imageURL)
+	if !ok {
+		return nil
+	}
+	err := s.client.Bucket(s.bucketName).Object(objectName).Delete(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil
+	}
+	return err
+}
+
+// DownloadImage: 保存済みの画像を読み込みます (UploadImage が返したURLでも、ファイル名だけでも可)
+// 大きすぎる画像は maxBytes で打ち切ってエラーにします
+func (s *StorageService) DownloadImage(ctx context.Context, imageURL string, maxBytes int64) ([]byte, error) {
+	objectName, ok := s.objectName(imageURL)
+	if !ok {
+		return nil, fmt.Errorf("image is not stored in bucket %s: %s", s.bucketName, imageURL)
+	}
+
+	rc, err := s.client.Bucket(s.bucketName).Object(objectName).NewReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, maxBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxBytes {
+		return nil, fmt.Errorf("image is larger than %d bytes", maxBytes)
+	}
+	return data, nil
+}
+
+// 画像のURLからバケット内のオブジェクト名を取り出す (このバケットの外のURLなら false)
+func (s *StorageService) objectName(imageURL string) (string, bool) {
+	objectName := strings.TrimPrefix(imageURL, fmt.Sprintf("https://storage.googleapis.com/%s/", s.bucketName))
+	if objectName == "" || strings.HasPrefix(objectName, "https://") || strings.HasPrefix(objectName, "http://") {
+		return "", false
+	}
+	return objectName, true
+}
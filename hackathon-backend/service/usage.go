@@ -0,0 +1,50 @@
+package service
+
+import (
+	"context"
+	"sync"
+)
+
+// Usage: 1リクエスト中のLLM呼び出しの集計
+type Usage struct {
+	Model            string
+	Calls            int
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// UsageRecorder: コンテキスト経由で各LLM実装から使用量を受け取る
+// LLMService のシグネチャを変えずに、呼び出し元 (AI系エンドポイント) がトークン数を集計するためのものです
+type UsageRecorder struct {
+	mu    sync.Mutex
+	usage Usage
+}
+
+type usageRecorderKey struct{}
+
+// WithUsageRecorder: 使用量を記録するコンテキストを作ります
+func WithUsageRecorder(ctx context.Context) (context.Context, *UsageRecorder) {
+	rec := &UsageRecorder{}
+	return context.WithValue(ctx, usageRecorderKey{}, rec), rec
+}
+
+// Usage: これまでに記録された使用量を返します
+func (r *UsageRecorder) Usage() Usage {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.usage
+}
+
+// recordUsage: コンテキストに UsageRecorder があれば使用量を加算する (なければ何もしない)
+func recordUsage(ctx context.Context, model string, promptTokens, completionTokens int) {
+	rec, ok := ctx.Value(usageRecorderKey{}).(*UsageRecorder)
+	if !ok {
+		return
+	}
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.usage.Model = model
+	rec.usage.Calls++
+	rec.usage.PromptTokens += promptTokens
+	rec.usage.CompletionTokens += completionTokens
+}
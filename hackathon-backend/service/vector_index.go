@@ -0,0 +1,121 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// VectorIndex: メモリ上で全件を比較するコサイン類似度のインデックス
+// 商品数が数万件程度までなら十分速いので、専用のベクトルDBは使わない
+type VectorIndex struct {
+	mu      sync.RWMutex
+	vectors map[string][]float32 // 正規化済み
+}
+
+// VectorMatch: 検索結果 (Score はコサイン類似度)
+type VectorMatch struct {
+	ID    string
+	Score float64
+}
+
+func NewVectorIndex() *VectorIndex {
+	return &VectorIndex{vectors: make(map[string][]float32)}
+}
+
+// Upsert: ベクトルを追加・更新します (長さ0のベクトルは無視)
+func (x *VectorIndex) Upsert(id string, vec []float32) {
+	n := normalize(vec)
+	if n == nil {
+		return
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.vectors[id] = n
+}
+
+func (x *VectorIndex) Delete(id string) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	delete(x.vectors, id)
+}
+
+// Replace: 中身を丸ごと入れ替えます (DBからの再読み込み用)
+func (x *VectorIndex) Replace(vectors map[string][]float32) {
+	m := make(map[string][]float32, len(vectors))
+	for id, v := range vectors {
+		if n := normalize(v); n != nil {
+			m[id] = n
+		}
+	}
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.vectors = m
+}
+
+func (x *VectorIndex) Get(id string) ([]float32, bool) {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	v, ok := x.vectors[id]
+	return v, ok
+}
+
+func (x *VectorIndex) Len() int {
+	x.mu.RLock()
+	defer x.mu.RUnlock()
+	return len(x.vectors)
+}
+
+// Search: query に近い順に最大 k 件返します (exclude に含まれるIDと次元の違うベクトルは除く)
+func (x *VectorIndex) Search(query []float32, k int, exclude map[string]bool) []VectorMatch {
+	q := normalize(query)
+	if q == nil || k <= 0 {
+		return nil
+	}
+
+	x.mu.RLock()
+	matches := make([]VectorMatch, 0, len(x.vectors))
+	for id, v := range x.vectors {
+		if exclude[id] || len(v) != len(q) {
+			continue
+		}
+		matches = append(matches, VectorMatch{ID: id, Score: dot(q, v)})
+	}
+	x.mu.RUnlock()
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].ID < matches[j].ID
+	})
+	if len(matches) > k {
+		matches = matches[:k]
+	}
+	return matches
+}
+
+// 長さ1にしたコピーを返す (ゼロベクトルは nil)
+func normalize(v []float32) []float32 {
+	var sum float64
+	for _, f := range v {
+		sum += float64(f) * float64(f)
+	}
+	if sum == 0 {
+		return nil
+	}
+	norm := math.Sqrt(sum)
+	n := make([]float32, len(v))
+	for i, f := range v {
+		n[i] = float32(float64(f) / norm)
+	}
+	return n
+}
+
+func dot(a, b []float32) float64 {
+	var s float64
+	for i := range a {
+		s += float64(a[i]) * float64(b[i])
+	}
+	return s
+}
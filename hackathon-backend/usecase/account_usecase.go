@@ -0,0 +1,125 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"hackathon-backend/dao"
+	"hackathon-backend/model"
+	"hackathon-backend/service"
+)
+
+// 売れてからこの期間は取引中とみなす (退会させない・届け先を見せる)
+// (発送・受け取りの状態を持っていないため、期間で判断する)
+const tradeSettlementPeriod = 14 * 24 * time.Hour
+
+// エクスポートに入れる件数の上限 (検索履歴・フォロー)
+const exportListLimit = 10000
+
+// ErrAccountRestricted: 利用停止・BAN中は退会できない
+// (退会で status が消えると、同じ人が新しいアカウントで登録し直して停止を逃れられるため)
+var ErrAccountRestricted = errors.New("restricted accounts cannot be deleted")
+
+// TradesInProgressError: 取引中の商品があるため退会できない
+type TradesInProgressError struct {
+	ProductIDs []string
+}
+
+func (e *TradesInProgressError) Error() string {
+	return "you have trades in progress"
+}
+
+type AccountUsecase struct {
+	AccountDAO       *dao.AccountDao
+	UserDAO          *dao.UserDao
+	ProductDAO       *dao.ProductDao
+	MessageDAO       *dao.MessageDao
+	FollowDAO        *dao.FollowDao
+	SearchHistoryDAO *dao.SearchHistoryDao
+	AddressDAO       *dao.AddressDao
+	StorageService   *service.StorageService
+}
+
+func NewAccountUsecase(aDAO *dao.AccountDao, uDAO *dao.UserDao, pDAO *dao.ProductDao, mDAO *dao.MessageDao, fDAO *dao.FollowDao, shDAO *dao.SearchHistoryDao, adDAO *dao.AddressDao, sService *service.StorageService) *AccountUsecase {
+	return &AccountUsecase{
+		AccountDAO:       aDAO,
+		UserDAO:          uDAO,
+		ProductDAO:       pDAO,
+		MessageDAO:       mDAO,
+		FollowDAO:        fDAO,
+		SearchHistoryDAO: shDAO,
+		AddressDAO:       adDAO,
+		StorageService:   sService,
+	}
+}
+
+// Export: 本人のプロフィール・出品・購入・いいね・メッセージなどをまとめて返す
+func (u *AccountUsecase) Export(firebaseUID string) (*model.AccountExport, error) {
+	user, err := u.UserDAO.FindByFirebaseUID(firebaseUID)
+	if err != nil || user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	export := &model.AccountExport{ExportedAt: time.Now(), Profile: user}
+	if export.Listings, err = u.ProductDAO.FindByUserID(user.ID, user.ID); err != nil {
+		return nil, err
+	}
+	if export.Purchases, err = u.ProductDAO.FindByBuyerID(user.ID, user.ID); err != nil {
+		return nil, err
+	}
+	if export.Likes, err = u.ProductDAO.FindLikedProducts(user.ID, user.ID); err != nil {
+		return nil, err
+	}
+	if export.Messages, err = u.MessageDAO.FindAllByUserID(user.ID); err != nil {
+		return nil, err
+	}
+	if export.Following, err = u.FollowDAO.ListFollowing(user.ID, exportListLimit, 0); err != nil {
+		return nil, err
+	}
+	if export.SearchHistory, err = u.SearchHistoryDAO.FindRecent(user.ID, exportListLimit); err != nil {
+		return nil, err
+	}
+	if export.Addresses, err = u.AddressDAO.ListByUserID(user.ID); err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+// DeleteAccount: 退会する
+// 利用停止・BAN中なら ErrAccountRestricted、取引中の商品があれば *TradesInProgressError を返します。
+// プロフィールは匿名化し、売れた商品とメッセージは相手の記録として残します
+func (u *AccountUsecase) DeleteAccount(ctx context.Context, firebaseUID string) error {
+	user, err := u.UserDAO.FindByFirebaseUID(firebaseUID)
+	if err != nil || user == nil {
+		return errors.New("user not found")
+	}
+
+	if user.IsRestricted(time.Now()) {
+		return ErrAccountRestricted
+	}
+
+	// 1. 取引中の商品がないか
+	trades, err := u.AccountDAO.RecentTradeIDs(user.ID, time.Now().Add(-tradeSettlementPeriod))
+	if err != nil {
+		return err
+	}
+	if len(trades) > 0 {
+		return &TradesInProgressError{ProductIDs: trades}
+	}
+
+	// 2. 匿名化と関連データの削除
+	images, err := u.AccountDAO.Anonymize(user.ID)
+	if err != nil {
+		return err
+	}
+
+	// 3. 画像の削除 (DBは更新済みなので、失敗してもログに残して続ける)
+	for _, url := range images {
+		if err := u.StorageService.DeleteImage(ctx, url); err != nil {
+			log.Printf("account: failed to delete image %s of user %s: %v", url, user.ID, err)
+		}
+	}
+	return nil
+}
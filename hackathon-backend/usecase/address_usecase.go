@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log"
+	"math/rand"
+	"time"
+
+	"hackathon-backend/dao"
+	"hackathon-backend/model"
+
+	"github.com/oklog/ulid/v2"
+)
+
+var (
+	ErrTooManyAddresses = errors.New("too many addresses")
+	ErrShippingNotFound = errors.New("shipping address not found")
+)
+
+type AddressUsecase struct {
+	AddressDAO *dao.AddressDao
+	UserDAO    *dao.UserDao
+	ProductDAO *dao.ProductDao
+}
+
+func NewAddressUsecase(aDAO *dao.AddressDao, uDAO *dao.UserDao, pDAO *dao.ProductDao) *AddressUsecase {
+	return &AddressUsecase{
+		AddressDAO: aDAO,
+		UserDAO:    uDAO,
+		ProductDAO: pDAO,
+	}
+}
+
+// List: 自分の住所録
+func (u *AddressUsecase) List(firebaseUID string) ([]*model.Address, error) {
+	user, err := u.findUser(firebaseUID)
+	if err != nil {
+		return nil, err
+	}
+	return u.AddressDAO.ListByUserID(user.ID)
+}
+
+// Create: 住所を登録する (最初の1件は自動で「いつも使う住所」にする)
+func (u *AddressUsecase) Create(firebaseUID string, req *model.AddressReq) (*model.Address, error) {
+	user, err := u.findUser(firebaseUID)
+	if err != nil {
+		return nil, err
+	}
+	count, err := u.AddressDAO.CountByUserID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= model.MaxAddressesPerUser {
+		return nil, ErrTooManyAddresses
+	}
+
+	t := time.Now()
+	entropy := ulid.Monotonic(rand.New(rand.NewSource(t.UnixNano())), 0)
+	address := &model.Address{
+		ID:            ulid.MustNew(ulid.Timestamp(t), entropy).String(),
+		AddressFields: req.AddressFields,
+		IsDefault:     req.IsDefault || count == 0,
+		CreatedAt:     t,
+	}
+	if err := u.AddressDAO.Create(user.ID, address); err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+// Update: 住所を更新する
+func (u *AddressUsecase) Update(firebaseUID, addressID string, req *model.AddressReq) (*model.Address, error) {
+	user, err := u.findUser(firebaseUID)
+	if err != nil {
+		return nil, err
+	}
+	address, err := u.AddressDAO.FindByID(addressID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if address == nil {
+		return nil, ErrAddressNotFound
+	}
+
+	address.AddressFields = req.AddressFields
+	// いつも使う住所を外すことはできない (他の住所を「いつも使う」にすると外れる)
+	address.IsDefault = address.IsDefault || req.IsDefault
+	if err := u.AddressDAO.Update(user.ID, address); err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+// Delete: 住所を削除する (いつも使う住所を消したら、残りの一番古い住所がいつも使う住所になる)
+func (u *AddressUsecase) Delete(firebaseUID, addressID string) error {
+	user, err := u.findUser(firebaseUID)
+	if err != nil {
+		return err
+	}
+	err = u.AddressDAO.Delete(addressID, user.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrAddressNotFound
+	}
+	return err
+}
+
+// GetSaleShipping: 売れた商品の届け先 (出品者だけが見られる)
+// 存在しない商品・出品者以外・届け先が登録されていない取引は ErrShippingNotFound を返します
+func (u *AddressUsecase) GetSaleShipping(firebaseUID, productID string) (*model.SaleShipping, error) {
+	user, err := u.findUser(firebaseUID)
+	if err != nil {
+		return nil, err
+	}
+	product, err := u.ProductDAO.FindByID(productID, user.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrShippingNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if product == nil || product.UserID != user.ID {
+		return nil, ErrShippingNotFound
+	}
+	shipping, err := u.AddressDAO.FindSaleShipping(productID)
+	if err != nil {
+		return nil, err
+	}
+	if shipping == nil || shipping.BuyerID != product.BuyerID {
+		return nil, ErrShippingNotFound
+	}
+	return shipping, nil
+}
+
+// RedactCompleted: 取引が終わった (売れてから tradeSettlementPeriod 経った) 商品の届け先を消す
+func (u *AddressUsecase) RedactCompleted() error {
+	n, err := u.AddressDAO.RedactSaleShippingOlderThan(tradeSettlementPeriod)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		log.Printf("address: redacted %d shipping addresses", n)
+	}
+	return nil
+}
+
+// RunRedaction: interval ごとに RedactCompleted を実行します (ctx がキャンセルされるまで戻りません)
+func (u *AddressUsecase) RunRedaction(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := u.RedactCompleted(); err != nil {
+			log.Printf("address: failed to redact shipping addresses: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (u *AddressUsecase) findUser(firebaseUID string) (*model.User, error) {
+	user, err := u.UserDAO.FindByFirebaseUID(firebaseUID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+	return user, nil
+}
@@ -0,0 +1,280 @@
+package usecase
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"hackathon-backend/dao"
+	"hackathon-backend/model"
+
+	"github.com/oklog/ulid/v2"
+)
+
+var (
+	ErrForbidden           = errors.New("permission denied")
+	ErrAdminTargetNotFound = errors.New("target not found")
+	ErrAdminNoChange       = errors.New("target is already in that state")
+)
+
+// AdminUsecase: モデレーター・管理者による操作 (すべて監査ログに記録する)
+// actor はミドルウェアでロール確認済みのユーザーを受け取ります
+type AdminUsecase struct {
+	UserDAO     *dao.UserDao
+	ProductDAO  *dao.ProductDao
+	MessageDAO  *dao.MessageDao
+	AuditLogDAO *dao.AuditLogDao
+}
+
+func NewAdminUsecase(uDAO *dao.UserDao, pDAO *dao.ProductDao, mDAO *dao.MessageDao, aDAO *dao.AuditLogDao) *AdminUsecase {
+	return &AdminUsecase{
+		UserDAO:     uDAO,
+		ProductDAO:  pDAO,
+		MessageDAO:  mDAO,
+		AuditLogDAO: aDAO,
+	}
+}
+
+// SuspendUser: ユーザーを利用停止にする (until が nil なら解除するまで停止)
+func (u *AdminUsecase) SuspendUser(actor *model.User, targetUserID, reason string, until *time.Time) (*model.User, error) {
+	return u.restrictUser(actor, targetUserID, model.UserStatusSuspended, reason, until, model.AuditActionSuspendUser)
+}
+
+// BanUser: ユーザーをBANする (無期限)
+func (u *AdminUsecase) BanUser(actor *model.User, targetUserID, reason string) (*model.User, error) {
+	return u.restrictUser(actor, targetUserID, model.UserStatusBanned, reason, nil, model.AuditActionBanUser)
+}
+
+func (u *AdminUsecase) restrictUser(actor *model.User, targetUserID, status, reason string, until *time.Time, action string) (*model.User, error) {
+	target, err := u.findManageableUser(actor, targetUserID)
+	if err != nil {
+		return nil, err
+	}
+	if target.Status == model.UserStatusBanned {
+		return nil, ErrAdminNoChange
+	}
+
+	detail := reason
+	if until != nil {
+		detail = fmt.Sprintf("until=%s reason=%s", until.Format(time.RFC3339), reason)
+	}
+	err = audited(u.AuditLogDAO, actor, action, model.ReportTargetUser, target.ID, detail, func(tx *sql.Tx) error {
+		return u.UserDAO.UpdateStatus(tx, target.ID, status, reason, until)
+	})
+	if err != nil {
+		return nil, err
+	}
+	target.Status = status
+	target.StatusReason = reason
+	target.SuspendedUntil = until
+	return target, nil
+}
+
+// UnsuspendUser: 利用停止・BANを解除する
+func (u *AdminUsecase) UnsuspendUser(actor *model.User, targetUserID, reason string) (*model.User, error) {
+	target, err := u.findManageableUser(actor, targetUserID)
+	if err != nil {
+		return nil, err
+	}
+	if target.Status == model.UserStatusActive {
+		return nil, ErrAdminNoChange
+	}
+	// BANの解除は admin のみ
+	if target.Status == model.UserStatusBanned && !actor.HasRole(model.RoleAdmin) {
+		return nil, ErrForbidden
+	}
+
+	err = audited(u.AuditLogDAO, actor, model.AuditActionUnsuspendUser, model.ReportTargetUser, target.ID, reason, func(tx *sql.Tx) error {
+		return u.UserDAO.UpdateStatus(tx, target.ID, model.UserStatusActive, "", nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	target.Status = model.UserStatusActive
+	target.StatusReason = ""
+	target.SuspendedUntil = nil
+	return target, nil
+}
+
+// ChangeRole: ロールを変更する (admin のみ。自分自身は変更できない)
+func (u *AdminUsecase) ChangeRole(actor *model.User, targetUserID, role string) (*model.User, error) {
+	if !actor.HasRole(model.RoleAdmin) || actor.ID == targetUserID {
+		return nil, ErrForbidden
+	}
+
+	target, err := u.UserDAO.FindByID(targetUserID)
+	if err != nil {
+		return nil, err
+	}
+	if target == nil {
+		return nil, ErrAdminTargetNotFound
+	}
+	if target.Role == role {
+		return nil, ErrAdminNoChange
+	}
+
+	detail := fmt.Sprintf("%s -> %s", target.Role, role)
+	err = audited(u.AuditLogDAO, actor, model.AuditActionChangeRole, model.ReportTargetUser, target.ID, detail, func(tx *sql.Tx) error {
+		return u.UserDAO.UpdateRole(tx, target.ID, role)
+	})
+	if err != nil {
+		return nil, err
+	}
+	target.Role = role
+	return target, nil
+}
+
+// TakeDownProduct: 出品を取り下げる (出品者に関係なく実行できる)
+func (u *AdminUsecase) TakeDownProduct(actor *model.User, productID, reason string) error {
+	if err := u.checkProductExists(productID); err != nil {
+		return err
+	}
+	err := audited(u.AuditLogDAO, actor, model.AuditActionTakeDown, model.ReportTargetProduct, productID, reason, func(tx *sql.Tx) error {
+		return u.ProductDAO.TakeDown(tx, productID)
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrAdminNoChange
+	}
+	return err
+}
+
+// RestoreProduct: 取り下げを解除する
+func (u *AdminUsecase) RestoreProduct(actor *model.User, productID, reason string) error {
+	if err := u.checkProductExists(productID); err != nil {
+		return err
+	}
+	err := audited(u.AuditLogDAO, actor, model.AuditActionRestore, model.ReportTargetProduct, productID, reason, func(tx *sql.Tx) error {
+		return u.ProductDAO.Restore(tx, productID)
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrAdminNoChange
+	}
+	return err
+}
+
+// CancelPurchase: 取引を強制キャンセルし、商品を出品中に戻す
+func (u *AdminUsecase) CancelPurchase(actor *model.User, productID, reason string) error {
+	product, err := u.ProductDAO.FindByID(productID, "")
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrAdminTargetNotFound
+		}
+		return err
+	}
+	if product.BuyerID == "" {
+		return ErrAdminNoChange
+	}
+
+	// 誰の購入を取り消したか分かるように購入者IDも残す
+	detail := fmt.Sprintf("buyer=%s reason=%s", product.BuyerID, reason)
+	err = audited(u.AuditLogDAO, actor, model.AuditActionCancelPurchase, model.ReportTargetProduct, productID, detail, func(tx *sql.Tx) error {
+		return u.ProductDAO.CancelPurchase(tx, productID)
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrAdminNoChange
+	}
+	return err
+}
+
+// ViewChat: トラブル調査のため任意の2人のチャットを閲覧する
+func (u *AdminUsecase) ViewChat(actor *model.User, userA, userB, reason string) ([]*model.Message, error) {
+	messages, err := u.MessageDAO.GetMessagesBetween(userA, userB)
+	if err != nil {
+		return nil, err
+	}
+
+	// 閲覧は状態を変えないので、監査ログだけを書く
+	detail := fmt.Sprintf("partner=%s reason=%s", userB, reason)
+	err = audited(u.AuditLogDAO, actor, model.AuditActionViewChat, model.ReportTargetUser, userA, detail, func(tx *sql.Tx) error {
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// ListAuditLogs: 監査ログ一覧
+func (u *AdminUsecase) ListAuditLogs(actorID, targetType, targetID string, page, limit int) (*model.AuditLogPage, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 50
+	}
+	offset := (page - 1) * limit
+
+	logs, err := u.AuditLogDAO.List(actorID, targetType, targetID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	total, err := u.AuditLogDAO.Count(actorID, targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+	return &model.AuditLogPage{Logs: logs, Total: total}, nil
+}
+
+// 操作対象のユーザーを取得する (自分自身や、自分以上の権限を持つ相手は操作できない)
+func (u *AdminUsecase) findManageableUser(actor *model.User, targetUserID string) (*model.User, error) {
+	if actor.ID == targetUserID {
+		return nil, ErrForbidden
+	}
+	target, err := u.UserDAO.FindByID(targetUserID)
+	if err != nil {
+		return nil, err
+	}
+	// 退会したユーザーは操作できない (利用停止を解除して復活させない)
+	if target == nil || target.Status == model.UserStatusDeleted {
+		return nil, ErrAdminTargetNotFound
+	}
+	if target.HasRole(actor.Role) && !actor.HasRole(model.RoleAdmin) {
+		return nil, ErrForbidden
+	}
+	return target, nil
+}
+
+// 商品の存在確認
+func (u *AdminUsecase) checkProductExists(productID string) error {
+	if _, err := u.ProductDAO.FindByID(productID, ""); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrAdminTargetNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// audited: 管理操作 apply と監査ログを同じトランザクションで書く
+// 監査ログが書けなければ操作も取り消すので、記録のない管理操作は残りません
+func audited(aDAO *dao.AuditLogDao, actor *model.User, action, targetType, targetID, detail string, apply func(tx *sql.Tx) error) error {
+	tx, err := aDAO.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := apply(tx); err != nil {
+		return err
+	}
+
+	t := time.Now()
+	entropy := ulid.Monotonic(rand.New(rand.NewSource(t.UnixNano())), 0)
+	logID := ulid.MustNew(ulid.Timestamp(t), entropy).String()
+
+	err = aDAO.Create(tx, &model.AuditLog{
+		ID:         logID,
+		ActorID:    actor.ID,
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Detail:     detail,
+		CreatedAt:  t,
+	})
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
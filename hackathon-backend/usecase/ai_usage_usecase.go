@@ -0,0 +1,207 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"hackathon-backend/dao"
+	"hackathon-backend/model"
+	"hackathon-backend/service"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// ErrAIBusy: 同時実行数の上限に達していて、今はAIを呼べない
+var ErrAIBusy = errors.New("AI service is busy, please retry shortly")
+
+// AIQuotaExceededError: 1日の利用上限に達したときのエラー
+type AIQuotaExceededError struct {
+	Limit      int
+	RetryAfter time.Duration // 上限がリセットされるまでの時間
+}
+
+func (e *AIQuotaExceededError) Error() string {
+	return fmt.Sprintf("daily AI quota of %d requests exceeded", e.Limit)
+}
+
+// AIRateLimitedError: 自動審査つきの投稿が1分あたりの上限に達したときのエラー
+type AIRateLimitedError struct {
+	Limit      int
+	RetryAfter time.Duration
+}
+
+func (e *AIRateLimitedError) Error() string {
+	return fmt.Sprintf("too many posts: limit is %d per minute", e.Limit)
+}
+
+// AIUsageConfig: AI利用の制限と料金の設定
+type AIUsageConfig struct {
+	DailyQuota          int     // ユーザーごとの1日のリクエスト上限
+	MaxConcurrent       int     // サーバー全体での同時実行数の上限
+	ModerationPerMinute int     // 自動審査つきの投稿のユーザーごとの1分あたりの上限 (日次上限とは別枠)
+	InputCostPer        float64 // 入力100万トークンあたりの料金 (USD)
+	OutputCostPer       float64 // 出力100万トークンあたりの料金 (USD)
+}
+
+type AIUsageUsecase struct {
+	AIUsageDAO *dao.AIUsageDao
+	Config     AIUsageConfig
+	slots      chan struct{}
+
+	// 自動審査の回数 (ユーザーごとの1分間の窓。このプロセス内だけで数える)
+	mu                sync.Mutex
+	moderationWindows map[string]*rateWindow
+}
+
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// 窓の数がこれを超えたら、終わった窓を捨てる
+const maxModerationWindows = 10000
+
+func NewAIUsageUsecase(aDAO *dao.AIUsageDao, cfg AIUsageConfig) *AIUsageUsecase {
+	return &AIUsageUsecase{
+		AIUsageDAO:        aDAO,
+		Config:            cfg,
+		slots:             make(chan struct{}, cfg.MaxConcurrent),
+		moderationWindows: make(map[string]*rateWindow),
+	}
+}
+
+// Acquire: 同時実行数と日次上限を確認して実行枠を確保する
+// 日次上限の1回分はAIを呼ぶ前にDBで確保します (同時に来たリクエストでも上限を超えないように)
+// 成功したら残り回数と、処理の終了時に呼ぶ done を返します。AIを1度も呼ばなかったら done(false) で確保した1回分を戻します
+func (u *AIUsageUsecase) Acquire(userID string) (int, func(usedAI bool), error) {
+	// 空きがなければ待たずに断る (リクエストを溜め込まない)
+	select {
+	case u.slots <- struct{}{}:
+	default:
+		return 0, nil, ErrAIBusy
+	}
+	release := func() { <-u.slots }
+
+	now := time.Now()
+	start := startOfDay(now)
+	day := start.Format(time.DateOnly)
+
+	used, ok, err := u.AIUsageDAO.ReserveDaily(userID, day, u.Config.DailyQuota)
+	if err != nil {
+		release()
+		return 0, nil, err
+	}
+	if !ok {
+		release()
+		return 0, nil, &AIQuotaExceededError{
+			Limit:      u.Config.DailyQuota,
+			RetryAfter: start.AddDate(0, 0, 1).Sub(now),
+		}
+	}
+
+	done := func(usedAI bool) {
+		release()
+		if !usedAI {
+			if err := u.AIUsageDAO.RefundDaily(userID, day); err != nil {
+				log.Printf("ai usage: failed to refund quota for %s: %v", userID, err)
+			}
+		}
+	}
+	return u.Config.DailyQuota - used, done, nil
+}
+
+// AcquireModeration: 自動審査つきの投稿 (出品・更新・メッセージ送信) を1回分数える
+// 審査は投稿のたびに必要なので日次上限には数えず、1分あたりの回数だけ制限します
+// 同時実行数の枠も使いません (AI系の機能が混んでいても投稿はできるように)
+func (u *AIUsageUsecase) AcquireModeration(userID string) error {
+	now := time.Now()
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	w := u.moderationWindows[userID]
+	if w == nil || now.Sub(w.start) >= time.Minute {
+		if len(u.moderationWindows) >= maxModerationWindows {
+			for id, old := range u.moderationWindows {
+				if now.Sub(old.start) >= time.Minute {
+					delete(u.moderationWindows, id)
+				}
+			}
+		}
+		u.moderationWindows[userID] = &rateWindow{start: now, count: 1}
+		return nil
+	}
+	if w.count >= u.Config.ModerationPerMinute {
+		return &AIRateLimitedError{
+			Limit:      u.Config.ModerationPerMinute,
+			RetryAfter: w.start.Add(time.Minute).Sub(now),
+		}
+	}
+	w.count++
+	return nil
+}
+
+// Record: 1リクエスト分の使用量を保存する
+// LLMを1度も呼ばなかったリクエスト (説明文なしの価格提案など) は記録しません (上限の1回分は Acquire の done で戻す)
+func (u *AIUsageUsecase) Record(userID, endpoint string, usage service.Usage, latency time.Duration, failed bool) error {
+	if usage.Calls == 0 {
+		return nil
+	}
+
+	t := time.Now()
+	entropy := ulid.Monotonic(rand.New(rand.NewSource(t.UnixNano())), 0)
+
+	status := model.AIUsageStatusSuccess
+	if failed {
+		status = model.AIUsageStatusError
+	}
+	return u.AIUsageDAO.Create(&model.AIUsage{
+		ID:               ulid.MustNew(ulid.Timestamp(t), entropy).String(),
+		UserID:           userID,
+		Endpoint:         endpoint,
+		Model:            usage.Model,
+		Status:           status,
+		LatencyMs:        latency.Milliseconds(),
+		Calls:            usage.Calls,
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		CreatedAt:        t,
+	})
+}
+
+// SpendReport: 期間内のAI利用額をユーザーごとに集計する (admin用)
+func (u *AIUsageUsecase) SpendReport(from, to time.Time) (*model.AISpendReport, error) {
+	spends, err := u.AIUsageDAO.SumByUser(from, to, 100)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &model.AISpendReport{From: from, To: to, Users: spends}
+	for _, s := range spends {
+		s.EstimatedCostUSD = u.estimateCost(s.PromptTokens, s.CompletionTokens)
+		report.TotalRequests += s.Requests
+		report.EstimatedCostUSD += s.EstimatedCostUSD
+	}
+	sort.SliceStable(spends, func(i, j int) bool {
+		return spends[i].EstimatedCostUSD > spends[j].EstimatedCostUSD
+	})
+	if report.Users == nil {
+		report.Users = []*model.AISpendByUser{}
+	}
+	return report, nil
+}
+
+func (u *AIUsageUsecase) estimateCost(promptTokens, completionTokens int) float64 {
+	return (float64(promptTokens)*u.Config.InputCostPer + float64(completionTokens)*u.Config.OutputCostPer) / 1_000_000
+}
+
+// その日の0時 (サーバーのタイムゾーン)
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
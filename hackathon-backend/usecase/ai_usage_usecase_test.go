@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAcquireModeration(t *testing.T) {
+	u := NewAIUsageUsecase(nil, AIUsageConfig{MaxConcurrent: 1, ModerationPerMinute: 3})
+
+	for i := 0; i < 3; i++ {
+		if err := u.AcquireModeration("alice"); err != nil {
+			t.Fatalf("call %d: unexpected error %v", i+1, err)
+		}
+	}
+
+	err := u.AcquireModeration("alice")
+	var rate *AIRateLimitedError
+	if !errors.As(err, &rate) {
+		t.Fatalf("4th call: got %v, want AIRateLimitedError", err)
+	}
+	if rate.RetryAfter <= 0 || rate.RetryAfter > time.Minute {
+		t.Errorf("RetryAfter = %v, want within a minute", rate.RetryAfter)
+	}
+
+	// 他のユーザーは別に数える
+	if err := u.AcquireModeration("bob"); err != nil {
+		t.Errorf("other user: unexpected error %v", err)
+	}
+
+	// 窓が終わったらまた投稿できる
+	u.moderationWindows["alice"].start = u.moderationWindows["alice"].start.Add(-time.Minute - time.Second)
+	if err := u.AcquireModeration("alice"); err != nil {
+		t.Errorf("after window: unexpected error %v", err)
+	}
+}
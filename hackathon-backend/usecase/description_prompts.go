@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"hackathon-backend/model"
+)
+
+// 説明文生成のプロンプトテンプレート (バージョンごと)
+// 文言を変えるときは既存のバージョンを書き換えずに新しいバージョンを追加し、
+// latestDescriptionPromptVersion を切り替えてください (結果の比較や切り戻しのため)
+const latestDescriptionPromptVersion = "v2"
+
+var descriptionPromptTemplates = map[string]*template.Template{
+	// v1: 当初のプロンプト (丁寧語・200文字固定。口調などのオプションは無視)
+	"v1": newDescriptionTemplate("v1", `
+あなたはプロのコピーライターです。フリマアプリに出品するための魅力的な商品説明文を書いてください。
+
+【商品名】
+{{.Name}}
+
+【特徴・キーワード】
+{{.Keywords}}
+
+【条件】
+- ターゲットが欲しくなるような文章にする
+- 商品の状態や魅力が伝わるようにする
+- 丁寧語（です・ます調）を使う
+- 200文字以内で簡潔にまとめる
+{{template "output" .}}`),
+
+	// v2: 口調・文字数・言語・発送欄の有無を指定できる
+	"v2": newDescriptionTemplate("v2", `
+あなたはプロのコピーライターです。フリマアプリに出品するための魅力的な商品説明文を書いてください。
+
+【商品名】
+{{.Name}}
+
+【特徴・キーワード】
+{{.Keywords}}
+
+【条件】
+- ターゲットが欲しくなるような文章にする
+- 商品の魅力が伝わるようにする
+- 口調: {{.ToneInstruction}}
+- 言語: {{.LanguageName}}で書く
+- {{.Length}}文字程度にまとめる
+{{- if .IncludeShipping}}
+- 最後に「商品の状態」と「発送について」の段落を設ける (キーワードにない情報は「出品者が記入してください」と書き、推測で埋めない)
+{{- end}}
+{{template "output" .}}`),
+}
+
+// 出力形式は全バージョン共通
+// 複数案のときは構造化出力 (descriptionDraftsSchema) で受け取る
+const descriptionOutputTemplate = `{{define "output"}}
+【重要：出力形式】
+{{- if .JSON}}
+- drafts に説明文の案を{{.Drafts}}個入れてください。案ごとに書き出しや切り口を変えること。
+{{- else}}
+- 生成された説明文のみを出力してください。
+{{- end}}
+- 「はい、承知いたしました」や「以下の通り作成しました」などの挨拶や前置きは一切不要です。
+{{end}}`
+
+func newDescriptionTemplate(version, text string) *template.Template {
+	t := template.Must(template.New(version).Parse(descriptionOutputTemplate))
+	return template.Must(t.Parse(text))
+}
+
+// テンプレートに渡す値
+type descriptionPromptData struct {
+	Name            string
+	Keywords        string
+	ToneInstruction string
+	LanguageName    string
+	Length          int
+	IncludeShipping bool
+	Drafts          int
+	JSON            bool // true なら複数案をJSONで出力させる
+}
+
+var toneInstructions = map[string]string{
+	model.ToneFormal:       "丁寧語（です・ます調）",
+	model.ToneCasual:       "親しみやすいカジュアルな口調",
+	model.ToneEnthusiastic: "おすすめしたい気持ちが伝わる明るく熱のこもった口調",
+}
+
+var languageNames = map[string]string{
+	model.LanguageJa: "日本語",
+	model.LanguageEn: "英語",
+	model.LanguageZh: "中国語（簡体字）",
+}
+
+// renderDescriptionPrompt: リクエストの内容でプロンプトを組み立てる
+// 戻り値は使ったテンプレートのバージョンとプロンプト
+func renderDescriptionPrompt(req *model.GenerateReq, asJSON bool) (string, string, error) {
+	version := req.PromptVersion
+	if version == "" {
+		version = latestDescriptionPromptVersion
+	}
+	tmpl, ok := descriptionPromptTemplates[version]
+	if !ok {
+		return "", "", fmt.Errorf("%w: %q", ErrUnknownPromptVersion, version)
+	}
+
+	var sb strings.Builder
+	err := tmpl.Execute(&sb, descriptionPromptData{
+		Name:            req.Name,
+		Keywords:        req.Keywords,
+		ToneInstruction: toneInstructions[req.Tone],
+		LanguageName:    languageNames[req.Language],
+		Length:          req.Length,
+		IncludeShipping: req.IncludeShipping,
+		Drafts:          req.Drafts,
+		JSON:            asJSON,
+	})
+	if err != nil {
+		return "", "", err
+	}
+	return version, sb.String(), nil
+}
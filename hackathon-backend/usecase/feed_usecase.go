@@ -0,0 +1,365 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+
+	"hackathon-backend/dao"
+	"hackathon-backend/model"
+	"hackathon-backend/service"
+
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	// 並べる候補にする出品中の商品の数 (新しい順)
+	feedCandidateLimit = 500
+	// フィードに載せる最大件数
+	feedMaxItems = 300
+	// 並び順を保持しておく時間 (スクロール中に並びが変わらないようにする)
+	feedSnapshotTTL = 30 * time.Minute
+	// 未ログインの閲覧者が同じ急上昇順の並びを使い回す時間 (feedSnapshotTTL より短くして、渡した feed_id が続きを読める間に切り替える)
+	feedAnonymousReuse = 10 * time.Minute
+	// 未ログイン向けに使い回している並びの feed_id を保存するキー
+	feedAnonymousKey = "feed:anonymous:current"
+)
+
+// ErrFeedExpired: feed_id の並びが期限切れ (またはほかのユーザーのもの)
+// 作り直した並びの続きを返すと前のページと重複するので、1ページ目から取り直してもらいます
+var ErrFeedExpired = errors.New("feed has expired, please reload from the first page")
+
+type FeedUsecase struct {
+	ProductDAO       *dao.ProductDao
+	UserDAO          *dao.UserDao
+	SearchHistoryDAO *dao.SearchHistoryDao
+	FollowDAO        *dao.FollowDao
+	StorageService   *service.StorageService
+	SimilarUsecase   *SimilarProductUsecase
+	Cache            service.Cache
+}
+
+func NewFeedUsecase(pDAO *dao.ProductDao, uDAO *dao.UserDao, shDAO *dao.SearchHistoryDao, fDAO *dao.FollowDao, sService *service.StorageService, simUsecase *SimilarProductUsecase, cache service.Cache) *FeedUsecase {
+	return &FeedUsecase{
+		ProductDAO:       pDAO,
+		UserDAO:          uDAO,
+		SearchHistoryDAO: shDAO,
+		FollowDAO:        fDAO,
+		StorageService:   sService,
+		SimilarUsecase:   simUsecase,
+		Cache:            cache,
+	}
+}
+
+// キャッシュに保存するフィードの並び
+type feedSnapshot struct {
+	IDs    []string `json:"ids"`
+	Source string   `json:"source"`
+}
+
+// 閲覧者の好み
+type feedProfile struct {
+	sellers map[string]float64 // 出品者ごとの重み
+	terms   map[string]float64 // 商品名・検索ワードの語ごとの重み
+	seen    map[string]bool    // いいね・購入済みの商品 (フィードには出さない)
+	vector  []float32          // いいね・購入した商品の埋め込みの平均
+}
+
+func (p *feedProfile) empty() bool {
+	return len(p.sellers) == 0 && len(p.terms) == 0 && p.vector == nil
+}
+
+// GetFeed: 閲覧者向けのおすすめ順で出品中の商品を返す
+// feedID があれば保存済みの並びからページを切り出し (期限切れなら ErrFeedExpired)、なければ並びを作って1ページ目を返します
+func (u *FeedUsecase) GetFeed(ctx context.Context, viewerFirebaseUID, feedID string, page, limit int) (*model.FeedPage, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 50 {
+		limit = 20
+	}
+
+	currentUserID := ""
+	if viewerFirebaseUID != "" {
+		user, err := u.UserDAO.FindByFirebaseUID(viewerFirebaseUID)
+		if err == nil && user != nil {
+			currentUserID = user.ID
+		}
+	}
+
+	var snapshot *feedSnapshot
+	if feedID != "" {
+		var ok bool
+		snapshot, ok = u.loadSnapshot(currentUserID, feedID)
+		if !ok {
+			return nil, ErrFeedExpired
+		}
+	} else {
+		var err error
+		snapshot, feedID, err = u.newSnapshot(ctx, currentUserID)
+		if err != nil {
+			return nil, err
+		}
+		page = 1
+	}
+
+	res := &model.FeedPage{Products: []*model.Product{}, FeedID: feedID, Page: page, Source: snapshot.Source}
+	start := (page - 1) * limit
+	if start >= len(snapshot.IDs) {
+		return res, nil
+	}
+	end := min(start+limit, len(snapshot.IDs))
+	res.HasMore = end < len(snapshot.IDs)
+
+	// 並びを作った後に売れた・非表示になった商品は除かれる
+	products, err := u.ProductDAO.FindSellingByIDs(snapshot.IDs[start:end], currentUserID)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range products {
+		if p.ImageURL != "" {
+			signedURL, err := u.StorageService.GenerateSignedURL(p.ImageURL)
+			if err == nil {
+				p.ImageURL = signedURL
+			}
+		}
+	}
+	if products != nil {
+		res.Products = products
+	}
+	return res, nil
+}
+
+// 1ページ目用の並びを作って保存する
+// 未ログインの閲覧者は全員同じ急上昇順になるので、アクセスのたびに保存せず一定時間使い回します
+func (u *FeedUsecase) newSnapshot(ctx context.Context, currentUserID string) (*feedSnapshot, string, error) {
+	if currentUserID == "" {
+		if b, ok := u.Cache.Get(feedAnonymousKey); ok {
+			if s, ok := u.loadSnapshot("", string(b)); ok {
+				return s, string(b), nil
+			}
+		}
+	}
+
+	snapshot, err := u.rank(ctx, currentUserID)
+	if err != nil {
+		return nil, "", err
+	}
+	feedID := u.saveSnapshot(currentUserID, snapshot)
+	if currentUserID == "" {
+		u.Cache.Set(feedAnonymousKey, []byte(feedID), feedAnonymousReuse)
+	}
+	return snapshot, feedID, nil
+}
+
+// 閲覧者の好みで候補を並べる (好みがわからなければ急上昇順)
+func (u *FeedUsecase) rank(ctx context.Context, currentUserID string) (*feedSnapshot, error) {
+	profile := &feedProfile{}
+	if currentUserID != "" {
+		var err error
+		profile, err = u.buildProfile(currentUserID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if profile.empty() {
+		return u.trending(currentUserID)
+	}
+
+	candidates, err := u.ProductDAO.Search(model.ProductFilter{Status: "selling"}, "", currentUserID, feedCandidateLimit, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// 好みの商品に近いものを埋め込みで探す (インデックスがなければこの項目は使わない)
+	similarity := make(map[string]float64)
+	if profile.vector != nil {
+		for _, m := range u.SimilarUsecase.Index.Search(profile.vector, feedCandidateLimit, profile.seen) {
+			similarity[m.ID] = m.Score
+		}
+	}
+
+	type scored struct {
+		id    string
+		score float64
+	}
+	now := time.Now()
+	var items []scored
+	for _, p := range candidates {
+		if p.UserID == currentUserID || profile.seen[p.ID] {
+			continue
+		}
+		items = append(items, scored{id: p.ID, score: profile.score(p, similarity[p.ID], now)})
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].score > items[j].score })
+
+	snapshot := &feedSnapshot{Source: model.FeedSourcePersonalized}
+	for i, it := range items {
+		if i >= feedMaxItems {
+			break
+		}
+		snapshot.IDs = append(snapshot.IDs, it.id)
+	}
+	return snapshot, nil
+}
+
+// 好みとの一致・新しさ・人気を足し合わせたスコア
+func (p *feedProfile) score(product *model.Product, similarity float64, now time.Time) float64 {
+	score := 0.6 * math.Min(p.sellers[product.UserID], 5)
+
+	name := strings.ToLower(product.Name)
+	description := strings.ToLower(product.Description)
+	termScore := 0.0
+	for t, w := range p.terms {
+		if strings.Contains(name, t) {
+			termScore += w
+		} else if strings.Contains(description, t) {
+			termScore += w * 0.4
+		}
+	}
+	score += 0.5 * math.Min(termScore, 6)
+
+	score += 3 * similarity
+	score += 0.3 * math.Log1p(float64(product.LikeCount))
+
+	// 新しい商品を上に (1週間で約1/3)
+	ageDays := now.Sub(product.CreatedAt).Hours() / 24
+	score += 1.5 * math.Exp(-ageDays/7)
+	return score
+}
+
+// いいね・購入履歴・フォロー・最近の検索から好みを作る
+func (u *FeedUsecase) buildProfile(currentUserID string) (*feedProfile, error) {
+	profile := &feedProfile{
+		sellers: make(map[string]float64),
+		terms:   make(map[string]float64),
+		seen:    make(map[string]bool),
+	}
+	var vectors [][]float32
+	addProducts := func(products []*model.Product, weight float64) {
+		for i, p := range products {
+			if i >= 50 {
+				break
+			}
+			profile.seen[p.ID] = true
+			profile.sellers[p.UserID] += weight
+			for _, t := range splitSearchTerms(p.Name) {
+				profile.terms[strings.ToLower(t)] += weight
+			}
+			if v, ok := u.SimilarUsecase.Index.Get(p.ID); ok {
+				vectors = append(vectors, v)
+			}
+		}
+	}
+
+	liked, err := u.ProductDAO.FindLikedProducts(currentUserID, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+	addProducts(liked, 1)
+
+	purchased, err := u.ProductDAO.FindByBuyerID(currentUserID, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+	addProducts(purchased, 2)
+
+	// フォロー中の出品者
+	following, err := u.FollowDAO.FollowingIDs(currentUserID)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range following {
+		profile.sellers[id] += 3
+	}
+
+	// 最近の検索ほど重くする
+	searches, err := u.SearchHistoryDAO.FindRecent(currentUserID, 10)
+	if err != nil {
+		return nil, err
+	}
+	for i, s := range searches {
+		q := ParseSearchQuery(s)
+		for _, t := range q.Keywords {
+			profile.terms[strings.ToLower(t)] += 2 * (1 - float64(i)/20)
+		}
+	}
+
+	profile.vector = meanVector(vectors)
+	return profile, nil
+}
+
+// 履歴がないときは急上昇順
+func (u *FeedUsecase) trending(currentUserID string) (*feedSnapshot, error) {
+	products, err := u.ProductDAO.Search(model.ProductFilter{Status: "selling"}, "trending", currentUserID, feedMaxItems, 0)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &feedSnapshot{Source: model.FeedSourceTrending}
+	for _, p := range products {
+		if p.UserID != currentUserID {
+			snapshot.IDs = append(snapshot.IDs, p.ID)
+		}
+	}
+	return snapshot, nil
+}
+
+func (u *FeedUsecase) loadSnapshot(currentUserID, feedID string) (*feedSnapshot, bool) {
+	b, ok := u.Cache.Get(feedCacheKey(currentUserID, feedID))
+	if !ok {
+		return nil, false
+	}
+	var s feedSnapshot
+	if err := json.Unmarshal(b, &s); err != nil {
+		return nil, false
+	}
+	return &s, true
+}
+
+// 並びを保存して feed_id を返す
+func (u *FeedUsecase) saveSnapshot(currentUserID string, s *feedSnapshot) string {
+	t := time.Now()
+	entropy := ulid.Monotonic(rand.New(rand.NewSource(t.UnixNano())), 0)
+	feedID := ulid.MustNew(ulid.Timestamp(t), entropy).String()
+
+	b, err := json.Marshal(s)
+	if err != nil {
+		log.Printf("feed: failed to encode snapshot: %v", err)
+		return feedID
+	}
+	u.Cache.Set(feedCacheKey(currentUserID, feedID), b, feedSnapshotTTL)
+	return feedID
+}
+
+// 他人の feed_id を使えないようにユーザーIDもキーに含める
+func feedCacheKey(currentUserID, feedID string) string {
+	return "feed:" + currentUserID + ":" + feedID
+}
+
+// 正規化済みのベクトルの平均 (なければ nil)
+func meanVector(vectors [][]float32) []float32 {
+	if len(vectors) == 0 {
+		return nil
+	}
+	mean := make([]float32, len(vectors[0]))
+	n := 0
+	for _, v := range vectors {
+		if len(v) != len(mean) {
+			continue
+		}
+		for i, f := range v {
+			mean[i] += f
+		}
+		n++
+	}
+	for i := range mean {
+		mean[i] /= float32(n)
+	}
+	return mean
+}
@@ -0,0 +1,165 @@
+package usecase
+
+import (
+	"errors"
+
+	"hackathon-backend/dao"
+	"hackathon-backend/model"
+	"hackathon-backend/service"
+)
+
+var (
+	ErrFollowTargetNotFound = errors.New("user not found")
+	ErrCannotFollowSelf     = errors.New("you cannot follow yourself")
+)
+
+type FollowUsecase struct {
+	FollowDAO      *dao.FollowDao
+	UserDAO        *dao.UserDao
+	ProductDAO     *dao.ProductDao
+	StorageService *service.StorageService
+}
+
+func NewFollowUsecase(fDAO *dao.FollowDao, uDAO *dao.UserDao, pDAO *dao.ProductDao, sService *service.StorageService) *FollowUsecase {
+	return &FollowUsecase{
+		FollowDAO:      fDAO,
+		UserDAO:        uDAO,
+		ProductDAO:     pDAO,
+		StorageService: sService,
+	}
+}
+
+// Follow: target (ID またはハンドル) をフォローする (フォロー済みでもエラーにしない)
+func (u *FollowUsecase) Follow(firebaseUID, target string) error {
+	user, targetUser, err := u.findPair(firebaseUID, target)
+	if err != nil {
+		return err
+	}
+	return u.FollowDAO.Follow(user.ID, targetUser.ID)
+}
+
+// Unfollow: フォローを外す (フォローしていなくてもエラーにしない)
+func (u *FollowUsecase) Unfollow(firebaseUID, target string) error {
+	user, targetUser, err := u.findPair(firebaseUID, target)
+	if err != nil {
+		return err
+	}
+	return u.FollowDAO.Unfollow(user.ID, targetUser.ID)
+}
+
+// ListFollowers: フォロワー一覧 (targetUserID は ID またはハンドル。"me" なら自分)
+func (u *FollowUsecase) ListFollowers(targetUserID, viewerFirebaseUID string, page, limit int) (*model.FollowUserPage, error) {
+	target, err := u.resolveUser(targetUserID, viewerFirebaseUID)
+	if err != nil {
+		return nil, err
+	}
+	page, limit = normalizePage(page, limit)
+
+	users, err := u.FollowDAO.ListFollowers(target.ID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+	total, err := u.FollowDAO.CountFollowers(target.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &model.FollowUserPage{Users: users, Total: total}, nil
+}
+
+// ListFollowing: フォロー中のユーザー一覧 (targetUserID は ID またはハンドル。"me" なら自分)
+func (u *FollowUsecase) ListFollowing(targetUserID, viewerFirebaseUID string, page, limit int) (*model.FollowUserPage, error) {
+	target, err := u.resolveUser(targetUserID, viewerFirebaseUID)
+	if err != nil {
+		return nil, err
+	}
+	page, limit = normalizePage(page, limit)
+
+	users, err := u.FollowDAO.ListFollowing(target.ID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+	total, err := u.FollowDAO.CountFollowing(target.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &model.FollowUserPage{Users: users, Total: total}, nil
+}
+
+// GetFollowingProducts: フォロー中の出品者の出品中の商品 (新着順)
+func (u *FollowUsecase) GetFollowingProducts(firebaseUID string, page, limit int) (*model.ProductPage, error) {
+	user, err := u.UserDAO.FindByFirebaseUID(firebaseUID)
+	if err != nil || user == nil {
+		return nil, errors.New("user not found")
+	}
+	page, limit = normalizePage(page, limit)
+
+	filter := model.ProductFilter{Status: "selling", FollowedBy: user.ID}
+	products, err := u.ProductDAO.Search(filter, "newest", user.ID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+	total, err := u.ProductDAO.SearchCount(filter)
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range products {
+		if p.ImageURL != "" {
+			signedURL, err := u.StorageService.GenerateSignedURL(p.ImageURL)
+			if err == nil {
+				p.ImageURL = signedURL
+			}
+		}
+	}
+	if products == nil {
+		products = []*model.Product{}
+	}
+	return &model.ProductPage{Products: products, Total: total}, nil
+}
+
+// 操作する人とフォロー相手 (ID またはハンドル) を取得する
+func (u *FollowUsecase) findPair(firebaseUID, target string) (*model.User, *model.User, error) {
+	user, err := u.UserDAO.FindByFirebaseUID(firebaseUID)
+	if err != nil || user == nil {
+		return nil, nil, errors.New("user not found")
+	}
+	targetUser, err := findByIDOrHandle(u.UserDAO, target)
+	if err != nil {
+		return nil, nil, err
+	}
+	if targetUser == nil {
+		return nil, nil, ErrFollowTargetNotFound
+	}
+	if user.ID == targetUser.ID {
+		return nil, nil, ErrCannotFollowSelf
+	}
+	return user, targetUser, nil
+}
+
+// "me" を閲覧者に読み替えてユーザーを取得する (それ以外は ID またはハンドル)
+func (u *FollowUsecase) resolveUser(userID, viewerFirebaseUID string) (*model.User, error) {
+	var user *model.User
+	var err error
+	if userID == "me" {
+		user, err = u.UserDAO.FindByFirebaseUID(viewerFirebaseUID)
+	} else {
+		user, err = findByIDOrHandle(u.UserDAO, userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrFollowTargetNotFound
+	}
+	return user, nil
+}
+
+// ページ番号と件数の補正 (件数は最大50)
+func normalizePage(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 50 {
+		limit = 20
+	}
+	return page, limit
+}
@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"bytes"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+// 写真チェックのしきい値
+const (
+	photoMinShortSide  = 480        // これより小さい画像は解像度不足
+	photoDarkLuminance = 60.0       // 平均輝度がこれ未満なら暗い
+	photoBrightLimit   = 225.0      // 平均輝度がこれを超えたら白飛び気味
+	photoBlurVariance  = 60.0       // ラプラシアンの分散がこれ未満ならピンぼけ
+	photoSampleSize    = 512        // 計算量を抑えるため長辺をこのくらいに間引いて見る
+	photoMaxPixels     = 40_000_000 // これより大きい画像はデコードしない (小さなファイルで巨大なサイズを宣言する画像対策)
+)
+
+// photoStats: 画像の明るさ・鮮明さなどの簡易計測結果
+type photoStats struct {
+	Width, Height int
+	Luminance     float64 // 平均輝度 (0〜255)
+	Sharpness     float64 // ラプラシアンの分散 (大きいほどくっきり)
+}
+
+// measurePhoto: 画像を読み込んで簡易的な品質の指標を計算する (jpeg/png/gif 以外は ok=false)
+// 画素数が photoMaxPixels を超える画像もデコードせずに ok=false にします
+func measurePhoto(imgData []byte) (*photoStats, bool) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(imgData))
+	if err != nil {
+		return nil, false
+	}
+	if int64(cfg.Width)*int64(cfg.Height) > photoMaxPixels {
+		return nil, false
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(imgData))
+	if err != nil {
+		return nil, false
+	}
+	b := img.Bounds()
+	stats := &photoStats{Width: b.Dx(), Height: b.Dy()}
+	if stats.Width == 0 || stats.Height == 0 {
+		return nil, false
+	}
+
+	// 間引きながらグレースケールにする
+	step := max(1, max(stats.Width, stats.Height)/photoSampleSize)
+	w, h := stats.Width/step, stats.Height/step
+	gray := make([]float64, w*h)
+	var sum float64
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			r, g, bl, _ := img.At(b.Min.X+x*step, b.Min.Y+y*step).RGBA()
+			l := (0.299*float64(r) + 0.587*float64(g) + 0.114*float64(bl)) / 257
+			gray[y*w+x] = l
+			sum += l
+		}
+	}
+	stats.Luminance = sum / float64(w*h)
+
+	// ラプラシアン (4近傍) の分散で鮮明さを見る
+	if w < 3 || h < 3 {
+		return stats, true
+	}
+	var lapSum, lapSqSum float64
+	n := 0
+	for y := 1; y < h-1; y++ {
+		for x := 1; x < w-1; x++ {
+			i := y*w + x
+			lap := gray[i-1] + gray[i+1] + gray[i-w] + gray[i+w] - 4*gray[i]
+			lapSum += lap
+			lapSqSum += lap * lap
+			n++
+		}
+	}
+	mean := lapSum / float64(n)
+	stats.Sharpness = lapSqSum/float64(n) - mean*mean
+	return stats, true
+}
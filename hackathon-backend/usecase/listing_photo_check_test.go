@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// 小さなPNGのヘッダーだけを書き換えて、巨大なサイズを宣言させる
+func withDeclaredSize(t *testing.T, data []byte, width, height uint32) []byte {
+	t.Helper()
+	out := bytes.Clone(data)
+	// シグネチャ(8) + 長さ(4) の後が IHDR チャンク
+	ihdr := out[12 : 12+4+13]
+	binary.BigEndian.PutUint32(ihdr[4:8], width)
+	binary.BigEndian.PutUint32(ihdr[8:12], height)
+	binary.BigEndian.PutUint32(out[12+4+13:], crc32.ChecksumIEEE(ihdr))
+	return out
+}
+
+func TestMeasurePhoto(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 64, 48))
+	for y := 0; y < 48; y++ {
+		for x := 0; x < 64; x++ {
+			img.SetGray(x, y, color.Gray{Y: uint8((x * 4) % 256)})
+		}
+	}
+	data := encodePNG(t, img)
+
+	stats, ok := measurePhoto(data)
+	if !ok {
+		t.Fatal("measurePhoto() ok = false for a valid PNG")
+	}
+	if stats.Width != 64 || stats.Height != 48 {
+		t.Errorf("size = %dx%d, want 64x48", stats.Width, stats.Height)
+	}
+
+	if _, ok := measurePhoto([]byte("not an image")); ok {
+		t.Error("measurePhoto() ok = true for non-image data")
+	}
+
+	bomb := withDeclaredSize(t, data, 100_000, 100_000)
+	if cfg, err := png.DecodeConfig(bytes.NewReader(bomb)); err != nil || cfg.Width != 100_000 {
+		t.Fatalf("test PNG header not rewritten: %v %v", cfg, err)
+	}
+	if _, ok := measurePhoto(bomb); ok {
+		t.Error("measurePhoto() ok = true for an image over the pixel cap")
+	}
+}
@@ -0,0 +1,242 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"regexp"
+
+	"hackathon-backend/dao"
+	"hackathon-backend/model"
+	"hackathon-backend/service"
+)
+
+var (
+	ErrListingNotFound = errors.New("product not found")
+	ErrNotListingOwner = errors.New("only the seller can review this listing")
+)
+
+// 説明文に入っていてほしい情報 (見つからなければ提案する)
+var (
+	sizeInfoPattern      = regexp.MustCompile(`(?i)サイズ|寸法|縦|横|高さ|幅|長さ|[0-9０-９]\s*(cm|ｃｍ|mm|ｍｍ|インチ|号|センチ)|\b(XS|S|M|L|XL|XXL)\b`)
+	conditionInfoPattern = regexp.MustCompile(`傷|キズ|汚れ|シミ|使用感|ダメージ|欠け|ほつれ|状態|新品|未使用|未開封|美品`)
+)
+
+type ListingReviewUsecase struct {
+	ProductDAO     *dao.ProductDao
+	UserDAO        *dao.UserDao
+	StorageService *service.StorageService
+	LLM            service.LLMService
+}
+
+func NewListingReviewUsecase(pDAO *dao.ProductDao, uDAO *dao.UserDao, sService *service.StorageService, llm service.LLMService) *ListingReviewUsecase {
+	return &ListingReviewUsecase{
+		ProductDAO:     pDAO,
+		UserDAO:        uDAO,
+		StorageService: sService,
+		LLM:            llm,
+	}
+}
+
+// ReviewProduct: 出品済みの商品を採点する (出品者本人のみ)
+func (u *ListingReviewUsecase) ReviewProduct(ctx context.Context, firebaseUID, productID string) (*model.ListingReview, error) {
+	user, err := u.UserDAO.FindByFirebaseUID(firebaseUID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrNotListingOwner
+	}
+
+	product, err := u.ProductDAO.FindByID(productID, user.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrListingNotFound
+		}
+		return nil, err
+	}
+	if product.UserID != user.ID {
+		return nil, ErrNotListingOwner
+	}
+
+	// 画像が読めなくても文章の採点はする
+	var imgData []byte
+	if product.ImageURL != "" {
+		imgData, err = u.StorageService.DownloadImage(ctx, product.ImageURL, 10<<20)
+		if err != nil {
+			log.Printf("listing review: failed to load image of %s: %v", productID, err)
+		}
+	}
+	return u.review(ctx, product.Name, product.Description, product.Price, imgData), nil
+}
+
+// ReviewDraft: 出品前の内容を採点する (画像は任意)
+func (u *ListingReviewUsecase) ReviewDraft(ctx context.Context, name, description string, price int, imgData []byte) *model.ListingReview {
+	return u.review(ctx, name, description, price, imgData)
+}
+
+func (u *ListingReviewUsecase) review(ctx context.Context, name, description string, price int, imgData []byte) *model.ListingReview {
+	res := &model.ListingReview{Suggestions: []*model.ListingSuggestion{}}
+
+	// 1. 機械的なチェック (減点とその理由)
+	penalties := map[string]int{}
+	addCheck := func(field string, penalty int, msg string) {
+		penalties[field] += penalty
+		res.Suggestions = append(res.Suggestions, &model.ListingSuggestion{Field: field, Message: msg, Source: model.SuggestionSourceCheck})
+	}
+	checkText(name, description, price, addCheck)
+	checkPhoto(imgData, addCheck)
+
+	// 2. AIによる採点 (失敗したら機械的なチェックだけで採点する)
+	scores := map[string]int{model.ListingFieldTitle: 100, model.ListingFieldDescription: 100, model.ListingFieldPhoto: 100}
+	aiRes, err := u.askAI(ctx, name, description, price, imgData)
+	if err != nil {
+		log.Printf("listing review: AI review failed: %v", err)
+	} else {
+		res.AIReviewed = true
+		scores[model.ListingFieldTitle] = aiRes.TitleScore
+		scores[model.ListingFieldDescription] = aiRes.DescriptionScore
+		if len(imgData) > 0 {
+			scores[model.ListingFieldPhoto] = aiRes.PhotoScore
+		}
+		for _, s := range aiRes.Suggestions {
+			if s.Message == "" {
+				continue
+			}
+			res.Suggestions = append(res.Suggestions, &model.ListingSuggestion{Field: s.Field, Message: s.Message, Source: model.SuggestionSourceAI})
+		}
+	}
+
+	// 3. スコアをまとめる (説明文を一番重く見る)
+	clamp := func(v int) int { return min(100, max(0, v)) }
+	res.TitleScore = clamp(scores[model.ListingFieldTitle] - penalties[model.ListingFieldTitle])
+	res.DescriptionScore = clamp(scores[model.ListingFieldDescription] - penalties[model.ListingFieldDescription])
+	res.PhotoScore = clamp(scores[model.ListingFieldPhoto] - penalties[model.ListingFieldPhoto])
+	res.Score = int(math.Round(0.25*float64(res.TitleScore) + 0.45*float64(res.DescriptionScore) + 0.3*float64(res.PhotoScore)))
+	return res
+}
+
+// 商品名・説明文・価格のチェック
+func checkText(name, description string, price int, add func(field string, penalty int, msg string)) {
+	switch n := len([]rune(name)); {
+	case n < 6:
+		add(model.ListingFieldTitle, 25, "商品名が短すぎます。ブランド名・型番・色などを入れると検索で見つかりやすくなります")
+	case n > 40:
+		add(model.ListingFieldTitle, 10, "商品名が長すぎます。大事な情報を前に寄せて40文字以内にまとめましょう")
+	}
+
+	switch n := len([]rune(description)); {
+	case n < 50:
+		add(model.ListingFieldDescription, 35, "説明文が短すぎます。状態・サイズ・購入時期などを書き足しましょう")
+	case n < 100:
+		add(model.ListingFieldDescription, 15, "説明文をもう少し詳しく書くと購入者が安心できます")
+	}
+	if !sizeInfoPattern.MatchString(description) {
+		add(model.ListingFieldDescription, 10, "サイズや寸法が書かれていません")
+	}
+	if !conditionInfoPattern.MatchString(description) {
+		add(model.ListingFieldDescription, 15, "傷・汚れの有無など、商品の状態が書かれていません")
+	}
+
+	if price > 0 && price < 300 {
+		add(model.ListingFieldPrice, 0, "300円未満だと送料や手数料で赤字になりやすいので、価格を見直してみてください")
+	}
+}
+
+// 写真のチェック (読み込めない形式・画素数が多すぎる画像は見ない)
+func checkPhoto(imgData []byte, add func(field string, penalty int, msg string)) {
+	if len(imgData) == 0 {
+		add(model.ListingFieldPhoto, 100, "写真がありません")
+		return
+	}
+	stats, ok := measurePhoto(imgData)
+	if !ok {
+		return
+	}
+	if min(stats.Width, stats.Height) < photoMinShortSide {
+		add(model.ListingFieldPhoto, 25, fmt.Sprintf("写真の解像度が低いです (%dx%d)。もっと大きな画像を使いましょう", stats.Width, stats.Height))
+	}
+	if stats.Luminance < photoDarkLuminance {
+		add(model.ListingFieldPhoto, 30, "写真が暗いです。明るい場所や自然光の下で撮り直しましょう")
+	} else if stats.Luminance > photoBrightLimit {
+		add(model.ListingFieldPhoto, 20, "写真が明るすぎて白飛びしています")
+	}
+	if stats.Sharpness < photoBlurVariance {
+		add(model.ListingFieldPhoto, 30, "写真がぼやけています。ピントを合わせて撮り直しましょう")
+	}
+}
+
+// 出品内容の採点の出力スキーマ
+var listingReviewSchema = &service.JSONSchema{
+	Type: "object",
+	Properties: map[string]*service.JSONSchema{
+		"title_score":       {Type: "integer", Description: "商品名の分かりやすさ (0〜100)"},
+		"description_score": {Type: "integer", Description: "説明文の充実度 (0〜100)"},
+		"photo_score":       {Type: "integer", Description: "写真の見やすさ (0〜100)。写真がなければ0"},
+		"suggestions": {Type: "array", Items: &service.JSONSchema{
+			Type: "object",
+			Properties: map[string]*service.JSONSchema{
+				"field":   {Type: "string", Enum: []string{model.ListingFieldTitle, model.ListingFieldDescription, model.ListingFieldPhoto, model.ListingFieldPrice}},
+				"message": {Type: "string"},
+			},
+			Required: []string{"field", "message"},
+		}},
+	},
+	Required: []string{"title_score", "description_score", "photo_score", "suggestions"},
+}
+
+type listingReviewAIResponse struct {
+	TitleScore       int `json:"title_score"`
+	DescriptionScore int `json:"description_score"`
+	PhotoScore       int `json:"photo_score"`
+	Suggestions      []struct {
+		Field   string `json:"field"`
+		Message string `json:"message"`
+	} `json:"suggestions"`
+}
+
+// AIに採点と改善提案をさせる
+func (u *ListingReviewUsecase) askAI(ctx context.Context, name, description string, price int, imgData []byte) (*listingReviewAIResponse, error) {
+	photoNote := "写真は添付されていません。"
+	mimeType := ""
+	if len(imgData) > 0 {
+		photoNote = "商品写真を添付しています。明るさ・ピント・背景・商品の写り方も評価してください。"
+		mimeType = http.DetectContentType(imgData)
+	}
+
+	prompt := fmt.Sprintf(`
+あなたはフリマアプリの出品アドバイザーです。以下の出品内容を購入者の目線で採点し、具体的な改善点を挙げてください。
+
+【商品名】
+%s
+
+【価格】
+%d円
+
+【説明文】
+%s
+
+【写真】
+%s
+
+【出力】
+- title_score / description_score / photo_score: 0〜100の整数
+- suggestions: 改善提案 (最大5個)。「サイズの記載がない」「傷や汚れについて書かれていない」「写真がぼやけている」など具体的に、日本語で簡潔に
+`, name, price, description, photoNote)
+
+	respText, err := u.LLM.GenerateJSON(ctx, prompt, listingReviewSchema, imgData, mimeType)
+	if err != nil {
+		return nil, err
+	}
+
+	var res listingReviewAIResponse
+	if err := json.Unmarshal([]byte(respText), &res); err != nil {
+		return nil, fmt.Errorf("failed to parse listing review response: %w", err)
+	}
+	return &res, nil
+}
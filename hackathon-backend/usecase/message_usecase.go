@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	"hackathon-backend/dao"
 	"hackathon-backend/model"
@@ -11,19 +12,23 @@ import (
 )
 
 type MessageUsecase struct {
-	MessageDAO *dao.MessageDao
-	UserDAO    *dao.UserDao
+	MessageDAO         *dao.MessageDao
+	UserDAO            *dao.UserDao
+	ModerationUsecase  *ModerationUsecase
+	TranslationUsecase *TranslationUsecase
 }
 
-func NewMessageUsecase(mDAO *dao.MessageDao, uDAO *dao.UserDao) *MessageUsecase {
+func NewMessageUsecase(mDAO *dao.MessageDao, uDAO *dao.UserDao, modUsecase *ModerationUsecase, tUsecase *TranslationUsecase) *MessageUsecase {
 	return &MessageUsecase{
-		MessageDAO: mDAO,
-		UserDAO:    uDAO,
+		MessageDAO:         mDAO,
+		UserDAO:            uDAO,
+		ModerationUsecase:  modUsecase,
+		TranslationUsecase: tUsecase,
 	}
 }
 
 // SendMessage: メッセージを送信
-func (u *MessageUsecase) SendMessage(senderFirebaseUID, receiverID, content, productID string) (*model.Message, error) {
+func (u *MessageUsecase) SendMessage(ctx context.Context, senderFirebaseUID, receiverID, content, productID string) (*model.Message, error) {
 	// 1. 送信者を特定
 	sender, err := u.UserDAO.FindByFirebaseUID(senderFirebaseUID)
 	if err != nil {
@@ -47,16 +52,28 @@ func (u *MessageUsecase) SendMessage(senderFirebaseUID, receiverID, content, pro
 		CreatedAt:  t,
 	}
 
-	// 3. 保存
+	// 3. 自動審査 (block なら送信させない)
+	modResult, err := u.ModerationUsecase.ReviewMessage(ctx, msgID, sender.ID, content)
+	if err != nil {
+		return nil, err
+	}
+	if modResult.Decision == model.ModerationBlock {
+		return nil, u.ModerationUsecase.Blocked(modResult)
+	}
+
+	// 4. 保存
 	if err := u.MessageDAO.Create(msg); err != nil {
 		return nil, err
 	}
+	u.ModerationUsecase.Record(modResult)
 
 	return msg, nil
 }
 
 // GetChatHistory: 特定の相手とのチャット履歴を取得
-func (u *MessageUsecase) GetChatHistory(myFirebaseUID, otherUserID string) ([]*model.Message, error) {
+// translate が true なら相手からのメッセージに翻訳を付けます
+// 翻訳先は language、空ならプロフィールの表示言語 (未設定なら日本語)
+func (u *MessageUsecase) GetChatHistory(ctx context.Context, myFirebaseUID, otherUserID string, translate bool, language string) ([]*model.Message, error) {
 	// 1. 自分を特定
 	me, err := u.UserDAO.FindByFirebaseUID(myFirebaseUID)
 	if err != nil {
@@ -67,7 +84,24 @@ func (u *MessageUsecase) GetChatHistory(myFirebaseUID, otherUserID string) ([]*m
 	}
 
 	// 2. 履歴取得
-	return u.MessageDAO.GetMessagesBetween(me.ID, otherUserID)
+	msgs, err := u.MessageDAO.GetMessagesBetween(me.ID, otherUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 翻訳
+	if translate {
+		if language == "" {
+			language = me.PreferredLanguage
+		}
+		if language == "" {
+			language = model.LanguageJa
+		}
+		if err := u.TranslationUsecase.TranslateMessages(ctx, msgs, me.ID, language); err != nil {
+			return nil, err
+		}
+	}
+	return msgs, nil
 }
 
 // GetChatList: チャット一覧（相手ごとの最新メッセージ）を取得
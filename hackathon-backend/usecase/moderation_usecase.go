@@ -0,0 +1,223 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/rand"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"hackathon-backend/dao"
+	"hackathon-backend/model"
+	"hackathon-backend/service"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// ContentBlockedError: 自動審査で投稿が拒否されたときのエラー
+type ContentBlockedError struct {
+	Result *model.ModerationResult
+}
+
+func (e *ContentBlockedError) Error() string {
+	return "content blocked by moderation: " + strings.Join(e.Result.Reasons, ", ")
+}
+
+// 連絡先っぽい文字列 (AIを呼ぶ前の簡易チェック)
+var contactInfoPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`),                               // メールアドレス
+	regexp.MustCompile(`(?:^|\D)0\d{9,10}(?:\D|$)`),                                                      // 電話番号 (ハイフンなし)
+	regexp.MustCompile(`0\d{1,4}[-‐ー]\d{1,4}[-‐ー]\d{3,4}`),                                               // 電話番号 (ハイフンあり)
+	regexp.MustCompile(`(?i)(line\s*id|line|ライン|インスタ|instagram|twitter)\s*[:：]\s*@?[A-Za-z0-9_.\-]{3,}`), // SNSアカウント
+}
+
+// containsContactInfo: 連絡先っぽい文字列が含まれているか
+func containsContactInfo(text string) bool {
+	for _, p := range contactInfoPatterns {
+		if p.MatchString(text) {
+			return true
+		}
+	}
+	return false
+}
+
+type ModerationUsecase struct {
+	ModerationDAO *dao.ModerationDao
+	LLM           service.LLMService
+}
+
+func NewModerationUsecase(mDAO *dao.ModerationDao, llm service.LLMService) *ModerationUsecase {
+	return &ModerationUsecase{
+		ModerationDAO: mDAO,
+		LLM:           llm,
+	}
+}
+
+// ReviewProduct: 出品内容 (商品名・説明・画像) を審査する
+// 画像がない場合 (更新時など) は imgData を nil で渡します。結果は出品を保存した後に Record で、block なら Blocked で保存してください
+func (u *ModerationUsecase) ReviewProduct(ctx context.Context, productID, authorID, name, description string, imgData []byte, mimeType string) (*model.ModerationResult, error) {
+	text := fmt.Sprintf("【商品名】\n%s\n\n【商品説明】\n%s", name, description)
+	return u.review(ctx, model.ReportTargetProduct, productID, authorID, "フリマアプリの出品内容", text, imgData, mimeType)
+}
+
+// ReviewMessage: チャットメッセージを審査する (結果はメッセージを保存した後に Record で、block なら Blocked で保存してください)
+func (u *ModerationUsecase) ReviewMessage(ctx context.Context, messageID, authorID, content string) (*model.ModerationResult, error) {
+	return u.review(ctx, model.ReportTargetMessage, messageID, authorID, "取引チャットのメッセージ", content, nil, "")
+}
+
+// Record: 審査結果を保存する
+// allow / flag は投稿を保存した後に呼んでください。失敗してもログに残すだけにします
+func (u *ModerationUsecase) Record(result *model.ModerationResult) {
+	if err := u.ModerationDAO.Create(result); err != nil {
+		log.Printf("moderation: failed to save result for %s %s: %v", result.TargetType, result.TargetID, err)
+	}
+}
+
+// Blocked: block された投稿の審査結果を保存して、返すエラーを作る
+// 投稿は残らないので、審査した内容 (Content) ごと保存してモデレーターが見られるようにします
+func (u *ModerationUsecase) Blocked(result *model.ModerationResult) error {
+	u.Record(result)
+	return &ContentBlockedError{Result: result}
+}
+
+// ListResults: モデレーター用の審査結果一覧
+func (u *ModerationUsecase) ListResults(decision, targetType, targetID string, page, limit int) (*model.ModerationResultPage, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 50
+	}
+	offset := (page - 1) * limit
+
+	results, err := u.ModerationDAO.List(decision, targetType, targetID, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	total, err := u.ModerationDAO.Count(decision, targetType, targetID)
+	if err != nil {
+		return nil, err
+	}
+	return &model.ModerationResultPage{Results: results, Total: total}, nil
+}
+
+func (u *ModerationUsecase) review(ctx context.Context, targetType, targetID, authorID, label, text string, imgData []byte, mimeType string) (*model.ModerationResult, error) {
+	t := time.Now()
+	entropy := ulid.Monotonic(rand.New(rand.NewSource(t.UnixNano())), 0)
+
+	result := &model.ModerationResult{
+		ID:         ulid.MustNew(ulid.Timestamp(t), entropy).String(),
+		TargetType: targetType,
+		TargetID:   targetID,
+		AuthorID:   authorID,
+		Decision:   model.ModerationAllow,
+		Categories: []string{},
+		Reasons:    []string{},
+		CreatedAt:  t,
+	}
+
+	// 1. 簡易チェック (連絡先の交換は要確認にする)
+	if containsContactInfo(text) {
+		mergeModeration(result, model.ModerationFlag, []string{model.ModerationCategoryContactInfo}, []string{"連絡先と思われる文字列が含まれています"})
+	}
+
+	// 2. AIによる審査 (失敗したら投稿は通すが、要確認にしてモデレーターに見てもらう)
+	aiRes, err := u.classify(ctx, label, text, imgData, mimeType)
+	if err != nil {
+		log.Printf("moderation: AI check failed for %s %s: %v", targetType, targetID, err)
+		mergeModeration(result, model.ModerationFlag, nil, []string{"自動審査を実行できなかったため、モデレーターの確認が必要です"})
+	} else {
+		mergeModeration(result, aiRes.Decision, aiRes.Categories, aiRes.Reasons)
+	}
+	// block だと投稿が残らないので、内容を結果と一緒に保存する
+	if result.Decision == model.ModerationBlock {
+		result.Content = text
+	}
+	return result, nil
+}
+
+// 自動審査の出力スキーマ
+var moderationSchema = &service.JSONSchema{
+	Type: "object",
+	Properties: map[string]*service.JSONSchema{
+		"decision": {Type: "string", Enum: []string{model.ModerationAllow, model.ModerationFlag, model.ModerationBlock}},
+		"categories": {Type: "array", Items: &service.JSONSchema{
+			Type: "string",
+			Enum: []string{model.ModerationCategoryProhibited, model.ModerationCategoryContactInfo, model.ModerationCategoryAbusive},
+		}},
+		"reasons": {Type: "array", Items: &service.JSONSchema{Type: "string"}},
+	},
+	Required: []string{"decision", "categories", "reasons"},
+}
+
+type moderationAIResponse struct {
+	Decision   string   `json:"decision"`
+	Categories []string `json:"categories"`
+	Reasons    []string `json:"reasons"`
+}
+
+// AIに判定させる
+func (u *ModerationUsecase) classify(ctx context.Context, label, text string, imgData []byte, mimeType string) (*moderationAIResponse, error) {
+	prompt := fmt.Sprintf(`
+あなたはフリマアプリのコンテンツ審査担当です。以下の%sが利用規約に違反していないか判定してください。
+
+%s
+
+【判定カテゴリ】
+- "prohibited_item": 出品禁止物 (偽ブランド品・コピー品、武器、薬物、医薬品、アダルト、盗品、チケットの高額転売など)
+- "contact_info": 個人の連絡先の交換 (電話番号、メールアドレス、LINE ID、SNSアカウント、アプリ外での取引への誘導など)
+- "abusive": 暴言・脅迫・差別・嫌がらせ
+
+【判定基準】
+- 明らかな違反は "block"、判断に迷うものは "flag"、問題なければ "allow"
+- 画像が添付されている場合は画像の内容も判定に含める
+
+【出力】
+- decision: "allow" / "flag" / "block" のいずれか
+- categories: 該当するカテゴリ (なければ空)
+- reasons: 判定理由 (日本語で簡潔に。なければ空)
+`, label, text)
+
+	respText, err := u.LLM.GenerateJSON(ctx, prompt, moderationSchema, imgData, mimeType)
+	if err != nil {
+		return nil, err
+	}
+
+	var res moderationAIResponse
+	if err := json.Unmarshal([]byte(respText), &res); err != nil {
+		return nil, fmt.Errorf("failed to parse moderation response: %w", err)
+	}
+	switch res.Decision {
+	case model.ModerationAllow, model.ModerationFlag, model.ModerationBlock:
+	default:
+		return nil, fmt.Errorf("unknown moderation decision: %q", res.Decision)
+	}
+	return &res, nil
+}
+
+// 判定を厳しい方に寄せて、カテゴリと理由をまとめる
+func mergeModeration(result *model.ModerationResult, decision string, categories, reasons []string) {
+	if moderationLevel(decision) > moderationLevel(result.Decision) {
+		result.Decision = decision
+	}
+	for _, c := range categories {
+		if !slices.Contains(result.Categories, c) {
+			result.Categories = append(result.Categories, c)
+		}
+	}
+	result.Reasons = append(result.Reasons, reasons...)
+}
+
+func moderationLevel(decision string) int {
+	switch decision {
+	case model.ModerationFlag:
+		return 1
+	case model.ModerationBlock:
+		return 2
+	}
+	return 0
+}
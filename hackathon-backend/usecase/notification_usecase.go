@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"errors"
+	"log"
+
+	"hackathon-backend/dao"
+	"hackathon-backend/model"
+)
+
+type NotificationUsecase struct {
+	NotificationDAO *dao.NotificationDao
+	UserDAO         *dao.UserDao
+}
+
+func NewNotificationUsecase(nDAO *dao.NotificationDao, uDAO *dao.UserDao) *NotificationUsecase {
+	return &NotificationUsecase{
+		NotificationDAO: nDAO,
+		UserDAO:         uDAO,
+	}
+}
+
+// NotifyNewListingAsync: 出品者のフォロワーに新着出品を知らせる (バックグラウンドで実行)
+// フォロワーが多くても出品のレスポンスを待たせないため。失敗はログに残すだけです
+func (u *NotificationUsecase) NotifyNewListingAsync(sellerID, productID string) {
+	go func() {
+		n, err := u.NotificationDAO.CreateForFollowers(sellerID, model.NotificationNewListing, productID)
+		if err != nil {
+			log.Printf("notification: failed to notify followers of %s: %v", sellerID, err)
+			return
+		}
+		if n > 0 {
+			log.Printf("notification: notified %d followers of new listing %s", n, productID)
+		}
+	}()
+}
+
+// List: 自分宛てのお知らせ
+func (u *NotificationUsecase) List(firebaseUID string, page, limit int) (*model.NotificationPage, error) {
+	user, err := u.UserDAO.FindByFirebaseUID(firebaseUID)
+	if err != nil || user == nil {
+		return nil, errors.New("user not found")
+	}
+	page, limit = normalizePage(page, limit)
+
+	notifications, err := u.NotificationDAO.List(user.ID, limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := u.NotificationDAO.CountUnread(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &model.NotificationPage{Notifications: notifications, UnreadCount: unread}, nil
+}
+
+// MarkAllRead: 自分宛てのお知らせをすべて既読にする
+func (u *NotificationUsecase) MarkAllRead(firebaseUID string) error {
+	user, err := u.UserDAO.FindByFirebaseUID(firebaseUID)
+	if err != nil || user == nil {
+		return errors.New("user not found")
+	}
+	return u.NotificationDAO.MarkAllRead(user.ID)
+}
@@ -2,107 +2,327 @@ package usecase
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"hackathon-backend/model"
 	"hackathon-backend/service"
+	"log"
 	"strings"
+	"time"
 )
 
+// 生成結果をキャッシュしておく時間
+const generationCacheTTL = 1 * time.Hour
+
 type ProductDescriptionUsecase struct {
-	GeminiService *service.GeminiService
+	LLM          service.LLMService
+	PriceUsecase *ProductPriceUsecase
+	Cache        service.Cache
 }
 
-func NewProductDescriptionUsecase(gService *service.GeminiService) *ProductDescriptionUsecase {
+func NewProductDescriptionUsecase(llm service.LLMService, priceUsecase *ProductPriceUsecase, cache service.Cache) *ProductDescriptionUsecase {
 	return &ProductDescriptionUsecase{
-		GeminiService: gService,
+		LLM:          llm,
+		PriceUsecase: priceUsecase,
+		Cache:        cache,
 	}
 }
 
+// ErrUnknownPromptVersion: 指定されたプロンプトテンプレートのバージョンが存在しない
+var ErrUnknownPromptVersion = errors.New("unknown prompt version")
+
 // Generate: 商品名とキーワードから説明文を生成 (テキストのみ)
-func (u *ProductDescriptionUsecase) Generate(ctx context.Context, name, keywords string) (string, error) {
-	prompt := fmt.Sprintf(`
-あなたはプロのコピーライターです。フリマアプリに出品するための魅力的な商品説明文を書いてください。
+// req.Drafts が2以上なら構造化出力で複数の案をまとめて生成します
+// 同じ入力の結果はキャッシュから返します。regenerate が true ならキャッシュを使わず作り直します
+func (u *ProductDescriptionUsecase) Generate(ctx context.Context, req *model.GenerateReq, regenerate bool) (*model.GenerateRes, error) {
+	asJSON := req.Drafts > 1
+	version, prompt, err := renderDescriptionPrompt(req, asJSON)
+	if err != nil {
+		return nil, err
+	}
+
+	key := descriptionCacheKey(version, req)
+	if !regenerate {
+		if drafts, ok := u.cachedDrafts(key); ok {
+			return newGenerateRes(drafts, version, true), nil
+		}
+	}
+
+	var drafts []string
+	if asJSON {
+		drafts, err = u.generateDrafts(ctx, prompt)
+	} else {
+		var desc string
+		desc, err = u.LLM.GenerateText(ctx, prompt)
+		drafts = []string{strings.TrimSpace(desc)}
+	}
+	if err != nil {
+		return nil, err
+	}
 
-【商品名】
-%s
+	u.cacheDrafts(key, drafts)
+	return newGenerateRes(drafts, version, false), nil
+}
 
-【特徴・キーワード】
-%s
+// GenerateStream: Generate のストリーミング版 (1案のみ)。生成された文章を届いた順に onChunk へ渡します
+// クライアントが切断して ctx がキャンセルされると生成も打ち切られます
+// キャッシュがあれば全文を1回の onChunk で渡します
+func (u *ProductDescriptionUsecase) GenerateStream(ctx context.Context, req *model.GenerateReq, regenerate bool, onChunk func(chunk string) error) error {
+	version, prompt, err := renderDescriptionPrompt(req, false)
+	if err != nil {
+		return err
+	}
 
-【条件】
-- ターゲットが欲しくなるような文章にする
-- 商品の状態や魅力が伝わるようにする
-- 丁寧語（です・ます調）を使う
-- 200文字以内で簡潔にまとめる
+	key := descriptionCacheKey(version, req)
+	if !regenerate {
+		if drafts, ok := u.cachedDrafts(key); ok {
+			return onChunk(drafts[0])
+		}
+	}
 
-【重要：出力形式】
-- 生成された説明文のみを出力してください。
-- 「はい、承知いたしました」や「以下の通り作成しました」などの挨拶や前置きは一切不要です。
-`, name, keywords)
+	// 最後まで生成できたときだけキャッシュする
+	var sb strings.Builder
+	err = u.LLM.GenerateTextStream(ctx, prompt, func(chunk string) error {
+		sb.WriteString(chunk)
+		return onChunk(chunk)
+	})
+	if err != nil {
+		return err
+	}
+	u.cacheDrafts(key, []string{strings.TrimSpace(sb.String())})
+	return nil
+}
 
-	return u.GeminiService.GenerateDescription(ctx, prompt)
+// 複数案の出力スキーマ
+var descriptionDraftsSchema = &service.JSONSchema{
+	Type: "object",
+	Properties: map[string]*service.JSONSchema{
+		"drafts": {Type: "array", Items: &service.JSONSchema{Type: "string"}, Description: "説明文の案"},
+	},
+	Required: []string{"drafts"},
+}
+
+// 複数案をまとめて生成する (空の案は捨てる。1つも残らなければエラー)
+func (u *ProductDescriptionUsecase) generateDrafts(ctx context.Context, prompt string) ([]string, error) {
+	respText, err := u.LLM.GenerateJSON(ctx, prompt, descriptionDraftsSchema, nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var res struct {
+		Drafts []string `json:"drafts"`
+	}
+	if err := json.Unmarshal([]byte(respText), &res); err != nil {
+		return nil, &AIOutputInvalidError{Fields: []model.FieldError{{Field: "_", Message: "response is not valid JSON for the schema: " + err.Error()}}}
+	}
+
+	var drafts []string
+	for _, d := range res.Drafts {
+		if d = strings.TrimSpace(d); d != "" {
+			drafts = append(drafts, d)
+		}
+	}
+	if len(drafts) == 0 {
+		return nil, &AIOutputInvalidError{Fields: []model.FieldError{{Field: "drafts", Message: "no drafts generated"}}}
+	}
+	return drafts, nil
+}
+
+func newGenerateRes(drafts []string, version string, cached bool) *model.GenerateRes {
+	return &model.GenerateRes{
+		Description:   drafts[0],
+		Drafts:        drafts,
+		PromptVersion: version,
+		Cached:        cached,
+	}
+}
+
+// 説明文のキャッシュキー (テンプレートのバージョンと全オプションを含める)
+func descriptionCacheKey(version string, req *model.GenerateReq) string {
+	opts := fmt.Sprintf("%s|%s|%d|%s|%t|%d", version, req.Tone, req.Length, req.Language, req.IncludeShipping, req.Drafts)
+	return cacheKey("desc", []byte(req.Name), []byte(req.Keywords), []byte(opts))
+}
+
+func (u *ProductDescriptionUsecase) cachedDrafts(key string) ([]string, bool) {
+	b, ok := u.Cache.Get(key)
+	if !ok {
+		return nil, false
+	}
+	var drafts []string
+	if err := json.Unmarshal(b, &drafts); err != nil || len(drafts) == 0 {
+		return nil, false
+	}
+	return drafts, true
+}
+
+func (u *ProductDescriptionUsecase) cacheDrafts(key string, drafts []string) {
+	if b, err := json.Marshal(drafts); err == nil {
+		u.Cache.Set(key, b, generationCacheTTL)
+	}
+}
+
+// 入力ごとのキャッシュキー (区切り文字を挟んで連結した値のハッシュ)
+func cacheKey(kind string, parts ...[]byte) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write(p)
+		h.Write([]byte{0})
+	}
+	return kind + ":" + hex.EncodeToString(h.Sum(nil))
+}
+
+// AIOutputInvalidError: AIの出力がスキーマの制約を満たさなかったときのエラー (再試行後も失敗した場合)
+type AIOutputInvalidError struct {
+	Fields []model.FieldError
+}
+
+func (e *AIOutputInvalidError) Error() string {
+	msgs := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		msgs = append(msgs, f.Field+": "+f.Message)
+	}
+	return "invalid AI output: " + strings.Join(msgs, ", ")
+}
+
+// 画像解析の出力スキーマ
+var imageInfoSchema = &service.JSONSchema{
+	Type: "object",
+	Properties: map[string]*service.JSONSchema{
+		"name":        {Type: "string", Description: "商品名 (30文字以内)"},
+		"price":       {Type: "integer", Description: "相場の参考値 (円、正の整数)"},
+		"keywords":    {Type: "array", Items: &service.JSONSchema{Type: "string"}, Description: "特徴を表すキーワード (1〜10個)"},
+		"description": {Type: "string", Description: "魅力的な商品説明文 (150文字程度。丁寧語で)"},
+	},
+	Required: []string{"name", "price", "keywords", "description"},
+}
+
+// 画像解析の出力
+type imageInfo struct {
+	Name        string   `json:"name"`
+	Price       int      `json:"price"`
+	Keywords    []string `json:"keywords"`
+	Description string   `json:"description"`
+}
+
+// validate: スキーマだけでは表せない制約を確認する
+func (i *imageInfo) validate() []model.FieldError {
+	var errs []model.FieldError
+	if n := len([]rune(strings.TrimSpace(i.Name))); n == 0 || n > 30 {
+		errs = append(errs, model.FieldError{Field: "name", Message: fmt.Sprintf("must be 1-30 chars, got %d", n)})
+	}
+	if i.Price <= 0 || i.Price > 9999999 {
+		errs = append(errs, model.FieldError{Field: "price", Message: fmt.Sprintf("must be between 1 and 9999999, got %d", i.Price)})
+	}
+	if n := len(i.Keywords); n == 0 || n > 10 {
+		errs = append(errs, model.FieldError{Field: "keywords", Message: fmt.Sprintf("must have 1-10 items, got %d", n)})
+	}
+	for _, k := range i.Keywords {
+		if strings.TrimSpace(k) == "" {
+			errs = append(errs, model.FieldError{Field: "keywords", Message: "must not contain empty keywords"})
+			break
+		}
+	}
+	if n := len([]rune(strings.TrimSpace(i.Description))); n < 20 || n > 400 {
+		errs = append(errs, model.FieldError{Field: "description", Message: fmt.Sprintf("must be 20-400 chars, got %d", n)})
+	}
+	return errs
 }
 
 // GenerateInfoFromImage: 画像から商品情報を抽出 (マルチモーダル)
-func (u *ProductDescriptionUsecase) GenerateInfoFromImage(ctx context.Context, imgData []byte, mimeType string) (*model.GenerateImageRes, error) {
-	// プロンプト：JSON形式での出力を強制します
-	prompt := `
-この商品画像を解析し、フリマアプリ出品用の情報をJSON形式で出力してください。
-以下のキーを含めてください：
-- "name": 商品名 (30文字以内)
-- "price": 推定価格 (数値のみ、円は不要。不明なら3000など適当な値)
-- "keywords": 特徴を表すキーワード (カンマ区切り)
-- "description": 魅力的な商品説明文 (150文字程度。丁寧語で)
-
-出力はJSONのみにしてください。Markdownのコードブロックは不要です。
-`
+// AIの解析結果は画像のハッシュごとにキャッシュし、regenerate が true なら作り直します
+// 価格は売れた類似商品があればその中央値を使い、なければAIの参考値を使います (取引実績は毎回見直す)
+func (u *ProductDescriptionUsecase) GenerateInfoFromImage(ctx context.Context, imgData []byte, mimeType string, regenerate bool) (*model.GenerateImageRes, error) {
+	// 1. 画像解析 (キャッシュがあればそれを使う)
+	key := cacheKey("image", imgData)
+	var info *imageInfo
+	cached := false
+	if !regenerate {
+		if b, ok := u.Cache.Get(key); ok {
+			if err := json.Unmarshal(b, &info); err == nil {
+				cached = true
+			} else {
+				log.Printf("generate from image: ignoring broken cache entry: %v", err)
+			}
+		}
+	}
+	if !cached {
+		var err error
+		info, err = u.analyzeImage(ctx, imgData, mimeType)
+		if err != nil {
+			return nil, err
+		}
+		if b, err := json.Marshal(info); err == nil {
+			u.Cache.Set(key, b, generationCacheTTL)
+		}
+	}
 
-	// 1. Gemini呼び出し
-	respText, err := u.GeminiService.GenerateFromImage(ctx, prompt, imgData, mimeType)
+	res := &model.GenerateImageRes{
+		Name:        strings.TrimSpace(info.Name),
+		Price:       info.Price,
+		Keywords:    strings.Join(info.Keywords, ","),
+		Description: strings.TrimSpace(info.Description),
+		PriceSource: "ai",
+		Cached:      cached,
+	}
+
+	// 2. 過去の取引実績があれば価格を置き換える
+	suggestion, err := u.PriceUsecase.SuggestPrice(ctx, res.Name, res.Keywords, false)
 	if err != nil {
 		return nil, err
 	}
+	if suggestion != nil {
+		res.Price = suggestion.Median
+		res.PriceSource = "market"
+		res.PriceSuggestion = suggestion
+	}
+
+	// 3. 結果を返す
+	return res, nil
+}
+
+// AIに画像を解析させる
+// 構造化出力でJSONを受け取り、制約を満たさなければ1回だけ理由を添えて再生成します
+func (u *ProductDescriptionUsecase) analyzeImage(ctx context.Context, imgData []byte, mimeType string) (*imageInfo, error) {
+	prompt := `
+この商品画像を解析し、フリマアプリ出品用の情報を出力してください。
+- name: 商品名 (30文字以内)
+- price: 一般的な中古相場の参考値 (円、正の整数)
+- keywords: 特徴を表すキーワード (1〜10個)
+- description: 魅力的な商品説明文 (150文字程度。丁寧語で)
+`
+
+	var fieldErrs []model.FieldError
+	for attempt := 0; attempt < 2; attempt++ {
+		p := prompt
+		if attempt > 0 {
+			// 再試行: 前回の問題点を伝える
+			p += "\n前回の出力には以下の問題がありました。修正してください。\n"
+			for _, fe := range fieldErrs {
+				p += "- " + fe.Field + ": " + fe.Message + "\n"
+			}
+		}
+
+		// LLM呼び出し
+		respText, err := u.LLM.GenerateJSON(ctx, p, imageInfoSchema, imgData, mimeType)
+		if err != nil {
+			return nil, err
+		}
+
+		// パースと検証
+		var info imageInfo
+		if err := json.Unmarshal([]byte(respText), &info); err != nil {
+			fieldErrs = []model.FieldError{{Field: "_", Message: "response is not valid JSON for the schema: " + err.Error()}}
+			continue
+		}
+		fieldErrs = info.validate()
+		if len(fieldErrs) > 0 {
+			continue
+		}
+		return &info, nil
+	}
 
-	// 2. JSONの整形（```json 等が含まれていた場合の除去）
-	respText = strings.TrimSpace(respText)
-	respText = strings.TrimPrefix(respText, "```json")
-	respText = strings.TrimPrefix(respText, "```")
-	respText = strings.TrimSuffix(respText, "```")
-
-	// 3. JSONパース用の一時構造体
-	// (Geminiが price を文字列で返してくる場合と数値で返してくる場合の両方に対応するため interface{} で受けます)
-	type AiResponse struct {
-		Name        string      `json:"name"`
-		Price       interface{} `json:"price"`
-		Keywords    string      `json:"keywords"`
-		Description string      `json:"description"`
-	}
-
-	var aiRes AiResponse
-	if err := json.Unmarshal([]byte(respText), &aiRes); err != nil {
-		// パース失敗時はエラーを返す（あるいはログに出して空の結果を返すなど）
-		return nil, fmt.Errorf("failed to parse AI response: %w", err)
-	}
-
-	// 4. Priceをintに安全に変換
-	var priceInt int
-	switch v := aiRes.Price.(type) {
-	case float64:
-		priceInt = int(v)
-	case string:
-		// "3,000" や "3000円" などの表記揺れ対策として、数字以外を除去してから変換しても良いですが
-		// ここではシンプルに fmt.Sscanf で試みます
-		fmt.Sscanf(v, "%d", &priceInt)
-	case int:
-		priceInt = v
-	}
-
-	// 5. 結果を返す
-	return &model.GenerateImageRes{
-		Name:        aiRes.Name,
-		Price:       priceInt,
-		Keywords:    aiRes.Keywords,
-		Description: aiRes.Description,
-	}, nil
+	return nil, &AIOutputInvalidError{Fields: fieldErrs}
 }
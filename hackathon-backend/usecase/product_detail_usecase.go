@@ -1,6 +1,10 @@
 package usecase
 
 import (
+	"database/sql"
+	"log"
+	"time"
+
 	"hackathon-backend/dao"
 	"hackathon-backend/model"
 	"hackathon-backend/service"
@@ -10,19 +14,24 @@ type ProductDetailUsecase struct {
 	ProductDAO     *dao.ProductDao
 	UserDAO        *dao.UserDao
 	StorageService *service.StorageService
+	EventDAO       *dao.ProductEventDao
 }
 
-func NewProductDetailUsecase(pDAO *dao.ProductDao, uDAO *dao.UserDao, sService *service.StorageService) *ProductDetailUsecase {
-	return &ProductDetailUsecase{ProductDAO: pDAO, UserDAO: uDAO, StorageService: sService}
+func NewProductDetailUsecase(pDAO *dao.ProductDao, uDAO *dao.UserDao, sService *service.StorageService, eDAO *dao.ProductEventDao) *ProductDetailUsecase {
+	return &ProductDetailUsecase{ProductDAO: pDAO, UserDAO: uDAO, StorageService: sService, EventDAO: eDAO}
 }
 
-func (u *ProductDetailUsecase) GetProductByID(id string, viewerFirebaseUID string) (*model.Product, error) {
+// GetProductByID: 商品詳細
+// anonymousKey は未ログインの閲覧者を区別するためのキー (閲覧数を1日1回に数えるのに使う)
+func (u *ProductDetailUsecase) GetProductByID(id string, viewerFirebaseUID, anonymousKey string) (*model.Product, error) {
 	// 見ている人のIDを特定
 	currentUserID := ""
+	isModerator := false
 	if viewerFirebaseUID != "" {
 		user, err := u.UserDAO.FindByFirebaseUID(viewerFirebaseUID)
 		if err == nil && user != nil {
 			currentUserID = user.ID
+			isModerator = user.HasRole(model.RoleModerator)
 		}
 	}
 
@@ -32,6 +41,22 @@ func (u *ProductDetailUsecase) GetProductByID(id string, viewerFirebaseUID strin
 		return nil, err
 	}
 
+	// 取り下げられた商品は出品者・購入者・モデレーター以外には見せない
+	if product.IsTakenDown && product.UserID != currentUserID && product.BuyerID != currentUserID && !isModerator {
+		return nil, sql.ErrNoRows
+	}
+
+	// 閲覧を記録 (出品者本人は除き、閲覧者ごとに1日1回。失敗しても詳細は返す)
+	if product.UserID != currentUserID {
+		viewerKey := anonymousKey
+		if currentUserID != "" {
+			viewerKey = "user:" + currentUserID
+		}
+		if _, err := u.EventDAO.CreateView(product.ID, currentUserID, viewerKey, time.Now()); err != nil {
+			log.Printf("product detail: failed to record view: %v", err)
+		}
+	}
+
 	// 画像URL変換
 	if product.ImageURL != "" {
 		url, err := u.StorageService.GenerateSignedURL(product.ImageURL)
@@ -3,52 +3,71 @@ package usecase
 import (
 	"errors"
 	"hackathon-backend/dao"
+	"hackathon-backend/model"
+	"log"
 )
 
 type ProductLikeUsecase struct {
-	LikeDAO *dao.LikeDao
-	UserDAO *dao.UserDao
+	LikeDAO  *dao.LikeDao
+	UserDAO  *dao.UserDao
+	EventDAO *dao.ProductEventDao
 }
 
-func NewProductLikeUsecase(lDAO *dao.LikeDao, uDAO *dao.UserDao) *ProductLikeUsecase {
+func NewProductLikeUsecase(lDAO *dao.LikeDao, uDAO *dao.UserDao, eDAO *dao.ProductEventDao) *ProductLikeUsecase {
 	return &ProductLikeUsecase{
-		LikeDAO: lDAO,
-		UserDAO: uDAO,
+		LikeDAO:  lDAO,
+		UserDAO:  uDAO,
+		EventDAO: eDAO,
 	}
 }
 
 // ToggleLike: いいねを切り替える（していなければ追加、していれば解除）
-// 戻り値 bool: 最終的に「いいね状態(true)」になったか「解除(false)」されたか
-func (u *ProductLikeUsecase) ToggleLike(productID, firebaseUID string) (bool, error) {
-	// 1. ユーザー特定
-	user, err := u.UserDAO.FindByFirebaseUID(firebaseUID)
+// 戻り値: 最終的に「いいね状態(true)」になったか「解除(false)」されたか, 更新後のいいね数
+func (u *ProductLikeUsecase) ToggleLike(productID, firebaseUID string) (bool, int, error) {
+	user, err := u.findUser(firebaseUID)
 	if err != nil {
-		return false, err
+		return false, 0, err
 	}
-	if user == nil {
-		return false, errors.New("user not found")
+
+	// 状態の確認と切り替えは DAO で1つのトランザクションにまとめる (連打しても数がずれない)
+	liked, count, err := u.LikeDAO.ToggleLike(user.ID, productID)
+	if err != nil {
+		return false, 0, err
 	}
+	u.recordLikeEvent(productID, user.ID, liked)
+	return liked, count, nil
+}
 
-	// 2. 現在の状態を確認
-	hasLiked, err := u.LikeDAO.HasLiked(user.ID, productID)
+// Like: いいねする (いいね済みでもエラーにしない)
+func (u *ProductLikeUsecase) Like(productID, firebaseUID string) (int, error) {
+	user, err := u.findUser(firebaseUID)
 	if err != nil {
-		return false, err
+		return 0, err
 	}
+	added, count, err := u.LikeDAO.AddLike(user.ID, productID)
+	if err != nil {
+		return 0, err
+	}
+	if added {
+		u.recordLikeEvent(productID, user.ID, true)
+	}
+	return count, nil
+}
 
-	// 3. 切り替え実行
-	if hasLiked {
-		// 解除
-		if err := u.LikeDAO.RemoveLike(user.ID, productID); err != nil {
-			return false, err
-		}
-		return false, nil // 結果: OFF
-	} else {
-		// 追加
-		if err := u.LikeDAO.AddLike(user.ID, productID); err != nil {
-			return false, err
-		}
-		return true, nil // 結果: ON
+// Unlike: いいねを解除する (いいねしていなくてもエラーにしない)
+func (u *ProductLikeUsecase) Unlike(productID, firebaseUID string) (int, error) {
+	user, err := u.findUser(firebaseUID)
+	if err != nil {
+		return 0, err
+	}
+	removed, count, err := u.LikeDAO.RemoveLike(user.ID, productID)
+	if err != nil {
+		return 0, err
 	}
+	if removed {
+		u.recordLikeEvent(productID, user.ID, false)
+	}
+	return count, nil
 }
 
 // GetLikeStatus: 現在の状態を確認する
@@ -62,3 +81,27 @@ func (u *ProductLikeUsecase) GetLikeStatus(productID, firebaseUID string) (bool,
 	}
 	return u.LikeDAO.HasLiked(user.ID, productID)
 }
+
+func (u *ProductLikeUsecase) findUser(firebaseUID string) (*model.User, error) {
+	user, err := u.UserDAO.FindByFirebaseUID(firebaseUID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+	return user, nil
+}
+
+// 急上昇スコア用の記録 (解除したら取り消す。失敗してもいいね自体は成功)
+func (u *ProductLikeUsecase) recordLikeEvent(productID, userID string, liked bool) {
+	var err error
+	if liked {
+		err = u.EventDAO.Create(productID, userID, model.ProductEventLike)
+	} else {
+		err = u.EventDAO.Delete(productID, userID, model.ProductEventLike)
+	}
+	if err != nil {
+		log.Printf("like: failed to update like event: %v", err)
+	}
+}
@@ -0,0 +1,140 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"unicode"
+
+	"hackathon-backend/dao"
+	"hackathon-backend/model"
+	"hackathon-backend/service"
+)
+
+// 価格の目安を出すのに必要な最低件数
+const minPriceSamples = 3
+
+type ProductPriceUsecase struct {
+	ProductDAO *dao.ProductDao
+	LLM        service.LLMService
+}
+
+func NewProductPriceUsecase(pDAO *dao.ProductDao, llm service.LLMService) *ProductPriceUsecase {
+	return &ProductPriceUsecase{
+		ProductDAO: pDAO,
+		LLM:        llm,
+	}
+}
+
+// SuggestPrice: 売れた類似商品の価格から目安を計算する
+// 類似商品が minPriceSamples 件未満なら nil を返します
+func (u *ProductPriceUsecase) SuggestPrice(ctx context.Context, name, keywords string, explain bool) (*model.PriceSuggestion, error) {
+	terms := splitSearchTerms(name + "," + keywords)
+
+	comparables, err := u.ProductDAO.FindSoldComparables(terms, 30)
+	if err != nil {
+		return nil, err
+	}
+	if len(comparables) < minPriceSamples {
+		return nil, nil
+	}
+
+	prices := make([]int, len(comparables))
+	for i, c := range comparables {
+		prices[i] = c.Price
+	}
+	sort.Ints(prices)
+
+	suggestion := &model.PriceSuggestion{
+		Min:         prices[0],
+		Max:         prices[len(prices)-1],
+		Median:      percentile(prices, 0.5),
+		SampleSize:  len(prices),
+		Comparables: comparables,
+	}
+	// 件数が多いときは外れ値に引っ張られないよう四分位で範囲を出す
+	if len(prices) >= 8 {
+		suggestion.Min = percentile(prices, 0.25)
+		suggestion.Max = percentile(prices, 0.75)
+	}
+
+	if explain {
+		// 説明文は付加情報なので、失敗しても価格の目安は返す
+		text, err := u.explain(ctx, name, suggestion)
+		if err != nil {
+			log.Printf("price suggestion: failed to generate explanation: %v", err)
+		} else {
+			suggestion.Explanation = text
+		}
+	}
+	return suggestion, nil
+}
+
+// AIに価格の根拠を説明させる (価格そのものは決めさせない)
+func (u *ProductPriceUsecase) explain(ctx context.Context, name string, s *model.PriceSuggestion) (string, error) {
+	var sb strings.Builder
+	for i, c := range s.Comparables {
+		if i >= 10 {
+			break
+		}
+		fmt.Fprintf(&sb, "- %s: %d円\n", c.Name, c.Price)
+	}
+
+	prompt := fmt.Sprintf(`
+あなたはフリマアプリの出品アドバイザーです。以下の実際の取引実績をもとに、出品者に価格の目安を説明してください。
+
+【出品予定の商品】
+%s
+
+【計算済みの価格の目安】
+- 中央値: %d円
+- 目安の範囲: %d円〜%d円
+- 参考にした取引: %d件
+
+【参考にした取引 (一部)】
+%s
+【条件】
+- 上記の数字を変えたり、新しい価格を作ったりしないこと
+- 丁寧語（です・ます調）で100文字程度
+- 説明文のみを出力し、前置きは不要
+`, name, s.Median, s.Min, s.Max, s.SampleSize, sb.String())
+
+	return u.LLM.GenerateText(ctx, prompt)
+}
+
+// 商品名・キーワードを検索語に分割する (1文字の語は除外、最大8語)
+func splitSearchTerms(text string) []string {
+	fields := strings.FieldsFunc(text, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(",、，/・", r)
+	})
+
+	seen := make(map[string]bool)
+	var terms []string
+	for _, f := range fields {
+		if len([]rune(f)) < 2 || seen[f] {
+			continue
+		}
+		seen[f] = true
+		terms = append(terms, f)
+		if len(terms) == 8 {
+			break
+		}
+	}
+	return terms
+}
+
+// ソート済みの値から p (0〜1) の位置の値を返す
+func percentile(sorted []int, p float64) int {
+	if len(sorted) == 0 {
+		return 0
+	}
+	pos := p * float64(len(sorted)-1)
+	lower := int(pos)
+	if lower+1 >= len(sorted) {
+		return sorted[lower]
+	}
+	frac := pos - float64(lower)
+	return int(float64(sorted[lower]) + frac*float64(sorted[lower+1]-sorted[lower]) + 0.5)
+}
@@ -5,16 +5,26 @@ import (
 	"hackathon-backend/dao"
 )
 
+var (
+	ErrAddressRequired = errors.New("address_id is required")
+	ErrAddressNotFound = errors.New("address not found")
+)
+
 type ProductPurchaseUsecase struct {
 	ProductDAO *dao.ProductDao
 	UserDAO    *dao.UserDao
+	AddressDAO *dao.AddressDao
 }
 
-func NewProductPurchaseUsecase(pDAO *dao.ProductDao, uDAO *dao.UserDao) *ProductPurchaseUsecase {
-	return &ProductPurchaseUsecase{ProductDAO: pDAO, UserDAO: uDAO}
+func NewProductPurchaseUsecase(pDAO *dao.ProductDao, uDAO *dao.UserDao, aDAO *dao.AddressDao) *ProductPurchaseUsecase {
+	return &ProductPurchaseUsecase{ProductDAO: pDAO, UserDAO: uDAO, AddressDAO: aDAO}
 }
 
-func (u *ProductPurchaseUsecase) PurchaseProduct(productID, firebaseUID string) error {
+// PurchaseProduct: 商品を購入する (addressID は住所録の届け先。購入時点の内容を写して出品者に渡す)
+func (u *ProductPurchaseUsecase) PurchaseProduct(productID, firebaseUID, addressID string) error {
+	if addressID == "" {
+		return ErrAddressRequired
+	}
 	user, err := u.UserDAO.FindByFirebaseUID(firebaseUID)
 	if err != nil {
 		return err
@@ -36,5 +46,16 @@ func (u *ProductPurchaseUsecase) PurchaseProduct(productID, firebaseUID string)
 	if product.BuyerID != "" {
 		return errors.New("product is already sold out")
 	}
-	return u.ProductDAO.UpdateBuyerID(productID, user.ID)
+	if product.IsTakenDown {
+		return errors.New("product is not available")
+	}
+
+	address, err := u.AddressDAO.FindByID(addressID, user.ID)
+	if err != nil {
+		return err
+	}
+	if address == nil {
+		return ErrAddressNotFound
+	}
+	return u.ProductDAO.UpdateBuyerID(productID, user.ID, &address.AddressFields)
 }
@@ -1,10 +1,12 @@
 package usecase
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"io"
 	"math/rand"
+	"net/http"
 	"time"
 
 	"hackathon-backend/dao"
@@ -15,25 +17,31 @@ import (
 )
 
 type ProductRegisterUsecase struct {
-	ProductDAO     *dao.ProductDao
-	UserDAO        *dao.UserDao
-	StorageService *service.StorageService
+	ProductDAO        *dao.ProductDao
+	UserDAO           *dao.UserDao
+	StorageService    *service.StorageService
+	ModerationUsecase *ModerationUsecase
+	SimilarUsecase    *SimilarProductUsecase
+	NotifyUsecase     *NotificationUsecase
 }
 
-func NewProductRegisterUsecase(pDAO *dao.ProductDao, uDAO *dao.UserDao, sService *service.StorageService) *ProductRegisterUsecase {
+func NewProductRegisterUsecase(pDAO *dao.ProductDao, uDAO *dao.UserDao, sService *service.StorageService, mUsecase *ModerationUsecase, simUsecase *SimilarProductUsecase, nUsecase *NotificationUsecase) *ProductRegisterUsecase {
 	return &ProductRegisterUsecase{
-		ProductDAO:     pDAO,
-		UserDAO:        uDAO,
-		StorageService: sService,
+		ProductDAO:        pDAO,
+		UserDAO:           uDAO,
+		StorageService:    sService,
+		ModerationUsecase: mUsecase,
+		SimilarUsecase:    simUsecase,
+		NotifyUsecase:     nUsecase,
 	}
 }
 
 // UpdateProduct が商品登録のメインロジックです
-func (u *ProductRegisterUsecase) RegisterProduct(firebaseUID, name, description string, price int, imageFile io.Reader, imageFilename string) (*model.Product, error) {
+func (u *ProductRegisterUsecase) RegisterProduct(ctx context.Context, firebaseUID, name, description string, price int, imageFile io.Reader, imageFilename string) (*model.Product, error) {
 	// 1. Firebase UID から内部の User ULID を検索する
 	// ※UserDAO に FindByFirebaseUID(uid string) (*model.User, error) がある前提です
 	user, err := u.UserDAO.FindByFirebaseUID(firebaseUID)
-	if err != nil {
+	if err != nil || user == nil {
 		return nil, errors.New("ユーザーが見つかりませんでした")
 	}
 
@@ -47,15 +55,29 @@ func (u *ProductRegisterUsecase) RegisterProduct(firebaseUID, name, description
 	entropy := ulid.Monotonic(rand.New(rand.NewSource(t.UnixNano())), 0)
 	productID := ulid.MustNew(ulid.Timestamp(t), entropy).String()
 
-	// 3. 画像アップロード (ファイルがある場合のみ)
+	// 審査とアップロードの両方で使うので画像を読み込んでおく
+	imgData, err := io.ReadAll(imageFile)
+	if err != nil {
+		return nil, err
+	}
+
+	// 3. 自動審査 (block なら出品させない)
+	modResult, err := u.ModerationUsecase.ReviewProduct(ctx, productID, user.ID, name, description, imgData, http.DetectContentType(imgData))
+	if err != nil {
+		return nil, err
+	}
+	if modResult.Decision == model.ModerationBlock {
+		return nil, u.ModerationUsecase.Blocked(modResult)
+	}
+
+	// 4. 画像アップロード
 	var storedImageName string
 
 	// ファイル名が重複しないようにIDをプレフィックスにつける
 	// 例: products/01HXYZ..._cat.jpg
 	uploadPath := "products/" + productID + "_" + imageFilename
 
-	ctx := context.Background()
-	path, err := u.StorageService.UploadImage(ctx, imageFile, uploadPath)
+	path, err := u.StorageService.UploadImage(ctx, bytes.NewReader(imgData), uploadPath)
 	if err != nil {
 		return nil, err
 	}
@@ -71,10 +93,17 @@ func (u *ProductRegisterUsecase) RegisterProduct(firebaseUID, name, description
 		ImageURL:    storedImageName,
 	}
 
-	// 4. DAO に保存を依頼する
+	// 5. DAO に保存を依頼する
 	if err := u.ProductDAO.Create(newProduct); err != nil {
 		return nil, err
 	}
+	u.ModerationUsecase.Record(modResult)
+
+	// 6. 類似商品の検索用に埋め込みを作る (バックグラウンド)
+	u.SimilarUsecase.IndexProductAsync(productID, user.ID, name, description)
+
+	// 7. フォロワーに新着出品を知らせる (バックグラウンド)
+	u.NotifyUsecase.NotifyNewListingAsync(user.ID, productID)
 
 	return newProduct, nil
 }
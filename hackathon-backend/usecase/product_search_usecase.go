@@ -2,22 +2,27 @@ package usecase
 
 import (
 	"errors"
+	"log"
+	"unicode/utf8"
+
 	"hackathon-backend/dao"
 	"hackathon-backend/model"
 	"hackathon-backend/service"
 )
 
 type ProductSearchUsecase struct {
-	ProductDAO     *dao.ProductDao
-	UserDAO        *dao.UserDao
-	StorageService *service.StorageService
+	ProductDAO       *dao.ProductDao
+	UserDAO          *dao.UserDao
+	StorageService   *service.StorageService
+	SearchHistoryDAO *dao.SearchHistoryDao
 }
 
-func NewProductSearchUsecase(pDAO *dao.ProductDao, uDAO *dao.UserDao, sService *service.StorageService) *ProductSearchUsecase {
+func NewProductSearchUsecase(pDAO *dao.ProductDao, uDAO *dao.UserDao, sService *service.StorageService, shDAO *dao.SearchHistoryDao) *ProductSearchUsecase {
 	return &ProductSearchUsecase{
-		ProductDAO:     pDAO,
-		UserDAO:        uDAO,
-		StorageService: sService,
+		ProductDAO:       pDAO,
+		UserDAO:          uDAO,
+		StorageService:   sService,
+		SearchHistoryDAO: shDAO,
 	}
 }
 
@@ -34,7 +39,8 @@ func (u *ProductSearchUsecase) getInternalUserID(firebaseUID string) string {
 }
 
 // SearchProduct: 商品検索
-func (u *ProductSearchUsecase) SearchProduct(keyword, sortOrder, status, viewerFirebaseUID string, page, limit int) (*model.ProductPage, error) {
+// keyword は商品名の部分一致、q は自然文から読み取った条件 (キーワード・価格・属性・状態・並び順)
+func (u *ProductSearchUsecase) SearchProduct(keyword string, q *model.SearchQuery, viewerFirebaseUID string, page, limit int) (*model.ProductPage, error) {
 	currentUserID := u.getInternalUserID(viewerFirebaseUID)
 
 	// ページ番号の補正
@@ -46,8 +52,17 @@ func (u *ProductSearchUsecase) SearchProduct(keyword, sortOrder, status, viewerF
 	}
 	offset := (page - 1) * limit
 
+	// おすすめフィード用に検索ワードを記録 (1ページ目だけ。失敗しても検索は続ける)
+	if text := searchText(keyword, q); currentUserID != "" && page == 1 && text != "" {
+		if err := u.SearchHistoryDAO.Create(currentUserID, text); err != nil {
+			log.Printf("search: failed to record search history: %v", err)
+		}
+	}
+
+	filter := searchFilter(keyword, q)
+
 	// 1. データ取得
-	products, err := u.ProductDAO.Search(keyword, sortOrder, status, currentUserID, "", limit, offset)
+	products, err := u.ProductDAO.Search(filter, q.Sort, currentUserID, limit, offset)
 	if err != nil {
 		return nil, err
 	}
@@ -55,7 +70,7 @@ func (u *ProductSearchUsecase) SearchProduct(keyword, sortOrder, status, viewerF
 	products, _ = u.processProducts(products, nil)
 
 	// 2. 件数取得
-	total, err := u.ProductDAO.SearchCount(keyword, status, "")
+	total, err := u.ProductDAO.SearchCount(filter)
 	if err != nil {
 		return nil, err
 	}
@@ -63,6 +78,24 @@ func (u *ProductSearchUsecase) SearchProduct(keyword, sortOrder, status, viewerF
 	return &model.ProductPage{Products: products, Total: total}, nil
 }
 
+// GetTrending: 急上昇の商品 (出品中のものを急上昇スコア順)
+func (u *ProductSearchUsecase) GetTrending(viewerFirebaseUID string, page, limit int) (*model.ProductPage, error) {
+	q := &model.SearchQuery{Status: "selling", Sort: "trending"}
+	return u.SearchProduct("", q, viewerFirebaseUID, page, limit)
+}
+
+// 記録する検索ワード (自然文検索なら元の文章)
+func searchText(keyword string, q *model.SearchQuery) string {
+	text := keyword
+	if text == "" {
+		text = q.Text
+	}
+	if utf8.RuneCountInString(text) > 255 {
+		text = string([]rune(text)[:255])
+	}
+	return text
+}
+
 // GetProductsByUserID: 特定のユーザーの商品一覧
 func (u *ProductSearchUsecase) GetProductsByUserID(targetUserID, sortOrder, status, viewerFirebaseUID string, page, limit int) (*model.ProductPage, error) {
 	currentUserID := u.getInternalUserID(viewerFirebaseUID)
@@ -75,13 +108,15 @@ func (u *ProductSearchUsecase) GetProductsByUserID(targetUserID, sortOrder, stat
 	}
 	offset := (page - 1) * limit
 
-	products, err := u.ProductDAO.Search("", sortOrder, status, currentUserID, targetUserID, limit, offset)
+	filter := model.ProductFilter{Status: status, TargetUserID: targetUserID}
+
+	products, err := u.ProductDAO.Search(filter, sortOrder, currentUserID, limit, offset)
 	if err != nil {
 		return nil, err
 	}
 	products, _ = u.processProducts(products, nil)
 
-	total, err := u.ProductDAO.SearchCount("", status, targetUserID)
+	total, err := u.ProductDAO.SearchCount(filter)
 	if err != nil {
 		return nil, err
 	}
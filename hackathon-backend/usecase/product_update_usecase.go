@@ -1,25 +1,34 @@
 package usecase
 
 import (
+	"context"
+	"database/sql"
 	"errors"
 	"hackathon-backend/dao"
 	"hackathon-backend/model"
 )
 
+// ErrNotProductOwner: 出品者以外が商品を更新しようとした
+var ErrNotProductOwner = errors.New("only the seller can edit this listing")
+
 type ProductUpdateUsecase struct {
-	ProductDAO *dao.ProductDao
-	UserDAO    *dao.UserDao
+	ProductDAO        *dao.ProductDao
+	UserDAO           *dao.UserDao
+	ModerationUsecase *ModerationUsecase
+	SimilarUsecase    *SimilarProductUsecase
 }
 
-func NewProductUpdateUsecase(pDAO *dao.ProductDao, uDAO *dao.UserDao) *ProductUpdateUsecase {
+func NewProductUpdateUsecase(pDAO *dao.ProductDao, uDAO *dao.UserDao, mUsecase *ModerationUsecase, simUsecase *SimilarProductUsecase) *ProductUpdateUsecase {
 	return &ProductUpdateUsecase{
-		ProductDAO: pDAO,
-		UserDAO:    uDAO,
+		ProductDAO:        pDAO,
+		UserDAO:           uDAO,
+		ModerationUsecase: mUsecase,
+		SimilarUsecase:    simUsecase,
 	}
 }
 
 // UpdateProduct は商品を更新します
-func (u *ProductUpdateUsecase) UpdateProduct(productID, firebaseUID, name, description string, price int) (*model.Product, error) {
+func (u *ProductUpdateUsecase) UpdateProduct(ctx context.Context, productID, firebaseUID, name, description string, price int) (*model.Product, error) {
 	// 1. Firebase UID から User ULID を特定
 	user, err := u.UserDAO.FindByFirebaseUID(firebaseUID)
 	if err != nil {
@@ -29,13 +38,38 @@ func (u *ProductUpdateUsecase) UpdateProduct(productID, firebaseUID, name, descr
 		return nil, errors.New("user not found")
 	}
 
-	// 2. 更新実行
+	// 2. 自分の商品か確認 (他人の商品に対して審査のAIを呼ばせない)
+	product, err := u.ProductDAO.FindByID(productID, user.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrListingNotFound
+		}
+		return nil, err
+	}
+	if product.UserID != user.ID {
+		return nil, ErrNotProductOwner
+	}
+
+	// 3. 自動審査 (block なら更新させない)
+	modResult, err := u.ModerationUsecase.ReviewProduct(ctx, productID, user.ID, name, description, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	if modResult.Decision == model.ModerationBlock {
+		return nil, u.ModerationUsecase.Blocked(modResult)
+	}
+
+	// 4. 更新実行
 	err = u.ProductDAO.Update(productID, user.ID, name, price, description)
 	if err != nil {
 		return nil, err
 	}
+	u.ModerationUsecase.Record(modResult)
+
+	// 商品名・説明文が変わったので埋め込みを作り直す (バックグラウンド)
+	u.SimilarUsecase.IndexProductAsync(productID, user.ID, name, description)
 
-	// 3. 更新後のデータを返却したい場合は再取得するか、入力値をそのまま返す
+	// 5. 更新後のデータを返却したい場合は再取得するか、入力値をそのまま返す
 	// ここではシンプルに入力値を元にモデルを返します（IDなどはそのまま）
 	return &model.Product{
 		ID:          productID,
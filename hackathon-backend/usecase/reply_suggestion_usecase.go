@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"hackathon-backend/dao"
+	"hackathon-backend/model"
+	"hackathon-backend/service"
+)
+
+const (
+	replyHistoryLimit = 15 // AIに渡す直近のメッセージ数
+	replyMaxLength    = 120
+	replyCount        = 3
+)
+
+// ErrReplyPartnerNotFound: 返信候補を作る相手のユーザーがいない
+var ErrReplyPartnerNotFound = errors.New("chat partner not found")
+
+type ReplySuggestionUsecase struct {
+	MessageDAO *dao.MessageDao
+	ProductDAO *dao.ProductDao
+	UserDAO    *dao.UserDao
+	LLM        service.LLMService
+}
+
+func NewReplySuggestionUsecase(mDAO *dao.MessageDao, pDAO *dao.ProductDao, uDAO *dao.UserDao, llm service.LLMService) *ReplySuggestionUsecase {
+	return &ReplySuggestionUsecase{
+		MessageDAO: mDAO,
+		ProductDAO: pDAO,
+		UserDAO:    uDAO,
+		LLM:        llm,
+	}
+}
+
+// SuggestReplies: 相手とのやり取りと商品情報から、返信の候補を3つ作る
+// 候補を返すだけで、メッセージの送信は一切しません。連絡先を含む候補は返しません
+func (u *ReplySuggestionUsecase) SuggestReplies(ctx context.Context, myFirebaseUID, partnerID string) (*model.SuggestedRepliesRes, error) {
+	me, err := u.UserDAO.FindByFirebaseUID(myFirebaseUID)
+	if err != nil {
+		return nil, err
+	}
+	if me == nil {
+		return nil, errors.New("user not found")
+	}
+	// AIを呼ぶ前に相手がいるか確かめる
+	partner, err := u.UserDAO.FindByID(partnerID)
+	if err != nil {
+		return nil, err
+	}
+	if partner == nil || partner.ID == me.ID {
+		return nil, ErrReplyPartnerNotFound
+	}
+
+	// 1. 直近の履歴 (取り消されたものは除く)
+	all, err := u.MessageDAO.GetMessagesBetween(me.ID, partnerID)
+	if err != nil {
+		return nil, err
+	}
+	var history []*model.Message
+	for _, m := range all {
+		if !m.IsDeleted {
+			history = append(history, m)
+		}
+	}
+	if len(history) > replyHistoryLimit {
+		history = history[len(history)-replyHistoryLimit:]
+	}
+
+	// 2. 話題の商品 (一番新しい商品付きメッセージ)
+	var product *model.Product
+	for i := len(all) - 1; i >= 0; i-- {
+		if all[i].ProductID == "" {
+			continue
+		}
+		p, err := u.ProductDAO.FindByID(all[i].ProductID, me.ID)
+		if err == nil {
+			product = p
+		}
+		break
+	}
+
+	// 3. 候補を生成
+	replies, err := u.generate(ctx, me.ID, history, product)
+	if err != nil {
+		return nil, err
+	}
+	res := &model.SuggestedRepliesRes{Replies: replies}
+	if product != nil {
+		res.ProductID = product.ID
+	}
+	return res, nil
+}
+
+// 返信候補の出力スキーマ
+var replySuggestionSchema = &service.JSONSchema{
+	Type: "object",
+	Properties: map[string]*service.JSONSchema{
+		"replies": {Type: "array", Items: &service.JSONSchema{Type: "string"}, Description: "返信の候補 (3個)"},
+	},
+	Required: []string{"replies"},
+}
+
+func (u *ReplySuggestionUsecase) generate(ctx context.Context, myID string, history []*model.Message, product *model.Product) ([]string, error) {
+	var sb strings.Builder
+	for _, m := range history {
+		speaker := "相手"
+		if m.SenderID == myID {
+			speaker = "あなた"
+		}
+		fmt.Fprintf(&sb, "%s: %s\n", speaker, m.Content)
+	}
+	if sb.Len() == 0 {
+		sb.WriteString("(まだやり取りはありません)\n")
+	}
+
+	productInfo := "(商品の情報はありません)"
+	role := "出品者または購入者"
+	if product != nil {
+		status := "販売中"
+		if product.BuyerID != "" {
+			status = "売約済み"
+		}
+		productInfo = fmt.Sprintf("商品名: %s\n価格: %d円\n状態: %s\n説明: %s", product.Name, product.Price, status, product.Description)
+		if product.UserID == myID {
+			role = "出品者"
+		} else {
+			role = "購入希望者"
+		}
+	}
+
+	prompt := fmt.Sprintf(`
+あなたはフリマアプリの取引チャットで、%sである「あなた」の返信を手伝うアシスタントです。
+以下のやり取りの続きとして「あなた」が送る返信の候補を%d個考えてください。
+
+【商品】
+%s
+
+【直近のやり取り】
+%s
+【条件】
+- 相手の最後のメッセージ (「まだ購入できますか」「値下げできますか」など) に直接答える
+- 商品情報にないこと (発送日・値下げ額など) は断定せず、確認する・調整するといった表現にする
+- それぞれ%d文字以内の丁寧な文章で、内容の方向性 (承諾・保留・お断りなど) を変える
+- 連絡先の交換やアプリ外での取引を促す内容は含めない
+`, role, replyCount, productInfo, sb.String(), replyMaxLength)
+
+	respText, err := u.LLM.GenerateJSON(ctx, prompt, replySuggestionSchema, nil, "")
+	if err != nil {
+		return nil, err
+	}
+
+	var res struct {
+		Replies []string `json:"replies"`
+	}
+	if err := json.Unmarshal([]byte(respText), &res); err != nil {
+		return nil, &AIOutputInvalidError{Fields: []model.FieldError{{Field: "_", Message: "response is not valid JSON for the schema: " + err.Error()}}}
+	}
+
+	replies := []string{}
+	for _, r := range res.Replies {
+		r = strings.TrimSpace(r)
+		if r == "" || len([]rune(r)) > replyMaxLength*2 {
+			continue
+		}
+		// 指示しても連絡先を書いてくることがあるので、送信時の審査と同じ簡易チェックで落とす
+		if containsContactInfo(r) {
+			continue
+		}
+		replies = append(replies, r)
+		if len(replies) == replyCount {
+			break
+		}
+	}
+	if len(replies) == 0 {
+		return nil, &AIOutputInvalidError{Fields: []model.FieldError{{Field: "replies", Message: "no usable replies generated"}}}
+	}
+	return replies, nil
+}
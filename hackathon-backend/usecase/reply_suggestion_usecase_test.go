@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"hackathon-backend/service"
+)
+
+func TestReplySuggestionGenerate(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name:     "fake default replies",
+			response: "",
+			want: []string{
+				"お問い合わせありがとうございます。まだ購入いただけます。",
+				"確認してご連絡しますので、少々お待ちください。",
+				"申し訳ありませんが、お値下げは難しいです。",
+			},
+		},
+		{
+			name:     "replies with contact info are dropped",
+			response: `{"replies": ["LINE: taro_123 で連絡ください", "まだ購入いただけます。", "taro@example.com までどうぞ"]}`,
+			want:     []string{"まだ購入いただけます。"},
+		},
+		{
+			name:     "empty and blank replies are dropped",
+			response: `{"replies": ["", "  ", "承知しました。"]}`,
+			want:     []string{"承知しました。"},
+		},
+		{
+			name:     "at most three replies",
+			response: `{"replies": ["1", "2", "3", "4"]}`,
+			want:     []string{"1", "2", "3"},
+		},
+		{
+			name:     "nothing usable",
+			response: `{"replies": ["電話は090-1234-5678です"]}`,
+			wantErr:  true,
+		},
+		{
+			name:     "invalid JSON",
+			response: `not json`,
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			llm := service.NewFakeLLMService()
+			if tt.response != "" {
+				llm.Rules = []service.FakeRule{{Contains: "返信の候補を", Response: tt.response}}
+			}
+			u := NewReplySuggestionUsecase(nil, nil, nil, llm)
+
+			got, err := u.generate(context.Background(), "me", nil, nil)
+			if tt.wantErr {
+				var invalid *AIOutputInvalidError
+				if !errors.As(err, &invalid) {
+					t.Fatalf("generate() error = %v, want AIOutputInvalidError", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("generate() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("generate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package usecase
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"hackathon-backend/dao"
+	"hackathon-backend/model"
+
+	"github.com/oklog/ulid/v2"
+)
+
+var (
+	ErrReportNotFound       = errors.New("report not found")
+	ErrReportTargetNotFound = errors.New("report target not found")
+	ErrDuplicateReport      = errors.New("you have already reported this")
+	ErrInvalidReportStatus  = errors.New("invalid report status transition")
+)
+
+type ReportUsecase struct {
+	ReportDAO   *dao.ReportDao
+	UserDAO     *dao.UserDao
+	ProductDAO  *dao.ProductDao
+	MessageDAO  *dao.MessageDao
+	AuditLogDAO *dao.AuditLogDao
+}
+
+func NewReportUsecase(rDAO *dao.ReportDao, uDAO *dao.UserDao, pDAO *dao.ProductDao, mDAO *dao.MessageDao, aDAO *dao.AuditLogDao) *ReportUsecase {
+	return &ReportUsecase{
+		ReportDAO:   rDAO,
+		UserDAO:     uDAO,
+		ProductDAO:  pDAO,
+		MessageDAO:  mDAO,
+		AuditLogDAO: aDAO,
+	}
+}
+
+// CreateReport: 商品・ユーザー・メッセージを通報する
+func (u *ReportUsecase) CreateReport(firebaseUID string, req model.CreateReportReq) (*model.Report, error) {
+	// 1. 通報者を特定
+	reporter, err := u.UserDAO.FindByFirebaseUID(firebaseUID)
+	if err != nil {
+		return nil, err
+	}
+	if reporter == nil {
+		return nil, errors.New("user not found")
+	}
+
+	// 2. 通報対象が存在するか確認
+	if err := u.checkTargetExists(req.TargetType, req.TargetID, reporter.ID); err != nil {
+		return nil, err
+	}
+
+	// 3. 同じ対象への重複通報を防ぐ
+	dup, err := u.ReportDAO.HasUnresolved(reporter.ID, req.TargetType, req.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	if dup {
+		return nil, ErrDuplicateReport
+	}
+
+	// 4. 保存
+	t := time.Now()
+	entropy := ulid.Monotonic(rand.New(rand.NewSource(t.UnixNano())), 0)
+	reportID := ulid.MustNew(ulid.Timestamp(t), entropy).String()
+
+	report := &model.Report{
+		ID:         reportID,
+		ReporterID: reporter.ID,
+		TargetType: req.TargetType,
+		TargetID:   req.TargetID,
+		Reason:     req.Reason,
+		Note:       req.Note,
+		Status:     model.ReportStatusOpen,
+		CreatedAt:  t,
+		UpdatedAt:  t,
+	}
+	if err := u.ReportDAO.Create(report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// 通報対象の存在確認 (メッセージは自分が当事者のものだけ通報できる)
+func (u *ReportUsecase) checkTargetExists(targetType, targetID, reporterID string) error {
+	switch targetType {
+	case model.ReportTargetProduct:
+		if _, err := u.ProductDAO.FindByID(targetID, ""); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrReportTargetNotFound
+			}
+			return err
+		}
+	case model.ReportTargetUser:
+		user, err := u.UserDAO.FindByID(targetID)
+		if err != nil {
+			return err
+		}
+		if user == nil {
+			return ErrReportTargetNotFound
+		}
+	case model.ReportTargetMessage:
+		msg, err := u.MessageDAO.FindByID(targetID)
+		if err != nil {
+			return err
+		}
+		if msg == nil || (msg.SenderID != reporterID && msg.ReceiverID != reporterID) {
+			return ErrReportTargetNotFound
+		}
+	}
+	return nil
+}
+
+// ListReports: モデレーター用の通報一覧 (権限の確認は呼び出し側で行う)
+func (u *ReportUsecase) ListReports(status, targetType string, page, limit int) (*model.ReportPage, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 20
+	}
+	offset := (page - 1) * limit
+
+	reports, err := u.ReportDAO.List(status, targetType, limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	total, err := u.ReportDAO.Count(status, targetType)
+	if err != nil {
+		return nil, err
+	}
+	return &model.ReportPage{Reports: reports, Total: total}, nil
+}
+
+// UpdateReport: 通報を確認中にする / 対応済みにする / 却下する
+// moderator は RequireRole で確認済みのユーザー
+func (u *ReportUsecase) UpdateReport(moderator *model.User, reportID string, req model.UpdateReportReq) (*model.Report, error) {
+	report, err := u.ReportDAO.FindByID(reportID)
+	if err != nil {
+		return nil, err
+	}
+	if report == nil {
+		return nil, ErrReportNotFound
+	}
+
+	// 対応済み・却下済みのものは変更できない
+	if report.Status == model.ReportStatusResolved || report.Status == model.ReportStatusDismissed {
+		return nil, ErrInvalidReportStatus
+	}
+	if report.Status == req.Status {
+		return nil, ErrInvalidReportStatus
+	}
+
+	// 対応状況の変更も管理操作として監査ログに残す
+	detail := fmt.Sprintf("%s -> %s resolution=%s", report.Status, req.Status, req.Resolution)
+	err = audited(u.AuditLogDAO, moderator, model.AuditActionUpdateReport, model.AuditTargetReport, report.ID, detail, func(tx *sql.Tx) error {
+		return u.ReportDAO.UpdateStatus(tx, report.ID, req.Status, moderator.ID, req.Resolution)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report.Status = req.Status
+	report.ModeratorID = moderator.ID
+	report.Resolution = req.Resolution
+	report.UpdatedAt = time.Now()
+	return report, nil
+}
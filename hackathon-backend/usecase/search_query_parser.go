@@ -0,0 +1,364 @@
+package usecase
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"hackathon-backend/model"
+)
+
+// 自然文の検索ワードを検索条件に分解するルール
+// 検索のたびにAIを呼ばないよう、よく使われる言い回しだけを正規表現で読み取ります
+
+// 色の表記ゆれ (キーは UI に返す値)
+var searchColors = []struct {
+	Value    string
+	Synonyms []string
+}{
+	{"white", []string{"ホワイト", "white", "白"}},
+	{"black", []string{"ブラック", "black", "黒"}},
+	{"red", []string{"レッド", "red", "赤"}},
+	{"blue", []string{"ブルー", "blue", "青"}},
+	{"navy", []string{"ネイビー", "navy", "紺"}},
+	{"green", []string{"グリーン", "green", "緑"}},
+	{"yellow", []string{"イエロー", "yellow", "黄色"}},
+	{"pink", []string{"ピンク", "pink"}},
+	{"gray", []string{"グレー", "gray", "grey", "灰色"}},
+	{"brown", []string{"ブラウン", "brown", "茶色"}},
+	{"beige", []string{"ベージュ", "beige"}},
+	{"purple", []string{"パープル", "purple", "紫"}},
+	{"orange", []string{"オレンジ", "orange"}},
+	{"silver", []string{"シルバー", "silver", "銀色"}},
+	{"gold", []string{"ゴールド", "gold", "金色"}},
+}
+
+// 金額: ¥5000 / 5,000円 / 5千円 / 1.5万円 / 5k yen
+const amountPattern = `[¥￥]?\s*(\d+(?:\.\d+)?)\s*(万|千|k\b)?\s*(?:円|yen\b)?`
+
+var (
+	sizePattern = regexp.MustCompile(`(?i)(?:size|サイズ)\s*[:：]?\s*(\d+(?:\.\d+)?|(?:xxl|xl|xs|l|m|s|free)\b|フリー)(?:\s*cm)?|(\d+(?:\.\d+)?)\s*cm\b|\b(xxl|xl|xs|l|m|s)\s*サイズ`)
+
+	priceRangePattern  = regexp.MustCompile(`(?i)` + amountPattern + `\s*(?:〜|~|-|から)\s*` + amountPattern + `(?:\s*(?:まで|の間))?`)
+	priceMaxPattern    = regexp.MustCompile(`(?i)` + amountPattern + `\s*(?:以下|以内|まで|未満)|(?:under|below|less than|up to|cheaper than)\s*` + amountPattern)
+	priceMinPattern    = regexp.MustCompile(`(?i)` + amountPattern + `\s*(?:以上|超え?)|(?:over|above|more than|at least)\s*` + amountPattern)
+	priceAroundPattern = regexp.MustCompile(`(?i)` + amountPattern + `\s*(?:くらい|ぐらい|前後|程度)|(?:around|about)\s*` + amountPattern)
+
+	statusSoldPattern    = regexp.MustCompile(`(?i)売り切れ|売切れ?|sold\s*out|\bsold\b`)
+	statusSellingPattern = regexp.MustCompile(`(?i)出品中|販売中|在庫あり|購入可能|for\s+sale|in\s+stock|\bavailable\b`)
+
+	sortPatterns = []struct {
+		Pattern *regexp.Regexp
+		Sort    string
+	}{
+		{regexp.MustCompile(`(?i)安い順|価格の?安い|cheapest|lowest\s+price`), "price_asc"},
+		{regexp.MustCompile(`(?i)高い順|価格の?高い|most\s+expensive|highest\s+price`), "price_desc"},
+		{regexp.MustCompile(`(?i)人気順?|popular`), "likes"},
+		{regexp.MustCompile(`(?i)新着順?|新しい順|newest|latest`), "newest"},
+	}
+)
+
+// キーワードとして扱わない語
+var searchStopWords = map[string]bool{
+	"the": true, "and": true, "with": true, "for": true, "in": true, "of": true, "yen": true, "円": true,
+	"size": true, "サイズ": true, "color": true, "colour": true, "price": true, "価格": true,
+}
+
+// ParseSearchQuery: 「白いスニーカー 5000円以下 サイズ27」のような文章を検索条件に分解します
+// 読み取れなかった部分はキーワードとして残します
+func ParseSearchQuery(text string) *model.SearchQuery {
+	q := &model.SearchQuery{Text: text, Keywords: []string{}, Attributes: []model.SearchAttribute{}}
+	rest := normalizeSearchText(text)
+
+	// サイズ (金額より先に読む: 「27cm」を金額と間違えないため)
+	rest = sizePattern.ReplaceAllStringFunc(rest, func(m string) string {
+		sub := sizePattern.FindStringSubmatch(m)
+		for _, v := range sub[1:] {
+			if v != "" {
+				q.Attributes = append(q.Attributes, model.SearchAttribute{Name: model.SearchAttrSize, Value: strings.ToUpper(v)})
+				break
+			}
+		}
+		return " "
+	})
+
+	// 金額 (範囲 → 上限 → 下限 → 目安 の順に読む)
+	rest = priceRangePattern.ReplaceAllStringFunc(rest, func(m string) string {
+		sub := priceRangePattern.FindStringSubmatch(m)
+		// 「27-28」のような数字だけの範囲は金額として扱わない
+		if !hasCurrencyMarker(m) {
+			return m
+		}
+		low, high := parseAmount(sub[1], sub[2]), parseAmount(sub[3], sub[4])
+		q.MinPrice, q.MaxPrice = min(low, high), max(low, high)
+		return " "
+	})
+	// 上限・下限・目安も「15以下」「3日以上」のような数字だけのものは金額として扱わない
+	rest = priceMaxPattern.ReplaceAllStringFunc(rest, func(m string) string {
+		if !hasCurrencyMarker(m) {
+			return m
+		}
+		sub := priceMaxPattern.FindStringSubmatch(m)
+		q.MaxPrice = firstAmount(sub[1:])
+		return " "
+	})
+	rest = priceMinPattern.ReplaceAllStringFunc(rest, func(m string) string {
+		if !hasCurrencyMarker(m) {
+			return m
+		}
+		sub := priceMinPattern.FindStringSubmatch(m)
+		q.MinPrice = firstAmount(sub[1:])
+		return " "
+	})
+	rest = priceAroundPattern.ReplaceAllStringFunc(rest, func(m string) string {
+		if !hasCurrencyMarker(m) {
+			return m
+		}
+		sub := priceAroundPattern.FindStringSubmatch(m)
+		amount := firstAmount(sub[1:])
+		// 目安の金額は前後2割を範囲にする
+		q.MinPrice, q.MaxPrice = amount*8/10, amount*12/10
+		return " "
+	})
+
+	// 状態
+	if statusSoldPattern.MatchString(rest) {
+		q.Status = "sold"
+		rest = statusSoldPattern.ReplaceAllString(rest, " ")
+	} else if statusSellingPattern.MatchString(rest) {
+		q.Status = "selling"
+		rest = statusSellingPattern.ReplaceAllString(rest, " ")
+	}
+
+	// 並び順
+	for _, s := range sortPatterns {
+		if s.Pattern.MatchString(rest) {
+			if q.Sort == "" {
+				q.Sort = s.Sort
+			}
+			rest = s.Pattern.ReplaceAllString(rest, " ")
+		}
+	}
+
+	// 色 (「白い」「白の」も色として読む)
+	for _, c := range searchColorPatterns {
+		for _, p := range c.Patterns {
+			var found bool
+			rest, found = p.replace(rest)
+			if !found {
+				continue
+			}
+			if !hasAttribute(q.Attributes, model.SearchAttrColor, c.Value) {
+				q.Attributes = append(q.Attributes, model.SearchAttribute{Name: model.SearchAttrColor, Value: c.Value})
+			}
+		}
+	}
+
+	// 残りはキーワード
+	for _, t := range splitSearchTerms(rest) {
+		if !searchStopWords[strings.ToLower(t)] {
+			q.Keywords = append(q.Keywords, t)
+		}
+	}
+	return q
+}
+
+// searchFilter: 検索条件をDAO用の条件に変換する
+// keyword は従来の商品名の部分一致
+func searchFilter(keyword string, q *model.SearchQuery) model.ProductFilter {
+	f := model.ProductFilter{
+		Keyword:  keyword,
+		MinPrice: q.MinPrice,
+		MaxPrice: q.MaxPrice,
+		Status:   q.Status,
+	}
+	for _, k := range q.Keywords {
+		f.TermGroups = append(f.TermGroups, []string{k})
+	}
+	// 同じ属性が複数あるとき (白か黒 など) はどれかに一致すればよい
+	groupIndex := make(map[string]int)
+	for _, a := range q.Attributes {
+		if i, ok := groupIndex[a.Name]; ok {
+			f.TermGroups[i] = append(f.TermGroups[i], attributeTerms(a)...)
+			continue
+		}
+		groupIndex[a.Name] = len(f.TermGroups)
+		f.TermGroups = append(f.TermGroups, attributeTerms(a))
+	}
+	return f
+}
+
+// 色ごとの正規表現 (検索のたびにコンパイルしないよう起動時に作っておく)
+var searchColorPatterns = compileColorPatterns()
+
+type colorPatterns struct {
+	Value    string
+	Patterns []*colorPattern
+}
+
+func compileColorPatterns() []colorPatterns {
+	colors := make([]colorPatterns, 0, len(searchColors))
+	for _, c := range searchColors {
+		cp := colorPatterns{Value: c.Value}
+		for _, syn := range c.Synonyms {
+			cp.Patterns = append(cp.Patterns, newColorPattern(syn))
+		}
+		colors = append(colors, cp)
+	}
+	return colors
+}
+
+// colorPattern: 1つの色の表記に一致させるパターン
+type colorPattern struct {
+	re *regexp.Regexp
+	// 漢字1文字の色 (白・青 など)。熟語の一部 (面白い・青森 など) には一致させない
+	singleKanji bool
+}
+
+// 英語の色名は単語の途中 (shred など) に一致させない
+func newColorPattern(syn string) *colorPattern {
+	if syn[0] < utf8.RuneSelf {
+		return &colorPattern{re: regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(syn) + `\b`)}
+	}
+	if utf8.RuneCountInString(syn) == 1 {
+		return &colorPattern{re: regexp.MustCompile(regexp.QuoteMeta(syn) + `色?(?:い|の)?`), singleKanji: true}
+	}
+	return &colorPattern{re: regexp.MustCompile(regexp.QuoteMeta(syn) + `(?:い|の)?`)}
+}
+
+// 色の表記を空白に置き換える (一致したかどうかも返す)
+func (p *colorPattern) replace(text string) (string, bool) {
+	var sb strings.Builder
+	found := false
+	last := 0
+	for _, loc := range p.re.FindAllStringIndex(text, -1) {
+		if p.singleKanji && !isColorKanjiBoundary(text, loc[0], loc[1]) {
+			continue
+		}
+		sb.WriteString(text[last:loc[0]])
+		sb.WriteString(" ")
+		last = loc[1]
+		found = true
+	}
+	if !found {
+		return text, false
+	}
+	sb.WriteString(text[last:])
+	return sb.String(), true
+}
+
+// 漢字1文字の色の後ろに続いてよいひらがな (「赤と黒」「白が欲しい」「真っ白な」「白っぽい」など)
+const colorKanjiParticles = "でがをとやかもはなっ"
+
+// text[start:end] の漢字1文字の色 (「い」「の」「色」が付いていることもある) が単独の語か
+// 前後に漢字が続くと熟語 (面白い・青森・白菜) 、後ろにひらがなが続くと別の語 (赤ちゃん・白ける) とみなします
+// ただし色どうしの組み合わせ (白黒) や、助詞が続く場合は色として読みます
+func isColorKanjiBoundary(text string, start, end int) bool {
+	if prev, _ := utf8.DecodeLastRuneInString(text[:start]); unicode.Is(unicode.Han, prev) && !isColorKanji(prev) {
+		return false
+	}
+	if _, size := utf8.DecodeRuneInString(text[start:]); start+size != end {
+		// 「白い」「白の」「白色」まで一致していれば、その後ろは見なくてよい
+		return true
+	}
+	next, _ := utf8.DecodeRuneInString(text[end:])
+	switch {
+	case unicode.Is(unicode.Han, next):
+		return isColorKanji(next)
+	case unicode.Is(unicode.Hiragana, next):
+		return strings.ContainsRune(colorKanjiParticles, next)
+	}
+	return true
+}
+
+// 漢字1文字で表す色か
+func isColorKanji(r rune) bool {
+	for _, c := range searchColors {
+		for _, syn := range c.Synonyms {
+			if s, size := utf8.DecodeRuneInString(syn); s == r && size == len(syn) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// 属性を商品名・説明文で探す語に展開する
+func attributeTerms(a model.SearchAttribute) []string {
+	switch a.Name {
+	case model.SearchAttrColor:
+		for _, c := range searchColors {
+			if c.Value == strings.ToLower(a.Value) {
+				return c.Synonyms
+			}
+		}
+	case model.SearchAttrSize:
+		// 「M」のような1文字のサイズはそのままだと何にでも一致するので表記を付ける
+		if _, err := strconv.ParseFloat(a.Value, 64); err != nil {
+			return []string{"サイズ" + a.Value, "サイズ " + a.Value, "サイズ:" + a.Value, "サイズ：" + a.Value, a.Value + "サイズ", "size " + a.Value}
+		}
+	}
+	return []string{a.Value}
+}
+
+// 全角の英数字・記号を半角にし、数字の桁区切りのカンマを取る
+func normalizeSearchText(text string) string {
+	text = strings.Map(func(r rune) rune {
+		if r >= '！' && r <= '～' && r != '￥' {
+			return r - '！' + '!'
+		}
+		if r == '　' {
+			return ' '
+		}
+		return r
+	}, text)
+
+	var sb strings.Builder
+	runes := []rune(text)
+	for i, r := range runes {
+		if r == ',' && i > 0 && i+1 < len(runes) && unicode.IsDigit(runes[i-1]) && unicode.IsDigit(runes[i+1]) {
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// 金額を表す記号・単位 (¥・円・万・千・yen) が付いているか
+func hasCurrencyMarker(m string) bool {
+	return strings.ContainsAny(m, "¥￥円万千") || strings.Contains(strings.ToLower(m), "yen")
+}
+
+// 金額の数字と単位 (万・千・k) から円にする
+func parseAmount(num, unit string) int {
+	f, err := strconv.ParseFloat(num, 64)
+	if err != nil {
+		return 0
+	}
+	switch strings.ToLower(unit) {
+	case "万":
+		f *= 10000
+	case "千", "k":
+		f *= 1000
+	}
+	return int(f)
+}
+
+// 2つの書き方のうちマッチした方の金額を返す (sub は 数字, 単位, 数字, 単位 の順)
+func firstAmount(sub []string) int {
+	if sub[0] != "" {
+		return parseAmount(sub[0], sub[1])
+	}
+	return parseAmount(sub[2], sub[3])
+}
+
+func hasAttribute(attrs []model.SearchAttribute, name, value string) bool {
+	for _, a := range attrs {
+		if a.Name == name && a.Value == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+
+	"hackathon-backend/model"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	color := func(v string) model.SearchAttribute {
+		return model.SearchAttribute{Name: model.SearchAttrColor, Value: v}
+	}
+	size := func(v string) model.SearchAttribute {
+		return model.SearchAttribute{Name: model.SearchAttrSize, Value: v}
+	}
+
+	tests := []struct {
+		text       string
+		keywords   []string
+		attributes []model.SearchAttribute
+		minPrice   int
+		maxPrice   int
+		status     string
+		sort       string
+	}{
+		{text: "白いスニーカー 5000円以下 サイズ27", keywords: []string{"スニーカー"}, attributes: []model.SearchAttribute{size("27"), color("white")}, maxPrice: 5000},
+		{text: "ワンピース 3000〜5000円", keywords: []string{"ワンピース"}, minPrice: 3000, maxPrice: 5000},
+		{text: "バッグ 1万円以上", keywords: []string{"バッグ"}, minPrice: 10000},
+		{text: "5千円くらい", minPrice: 4000, maxPrice: 6000},
+		{text: "under 5000 yen jacket", keywords: []string{"jacket"}, maxPrice: 5000},
+		{text: "Tシャツ Mサイズ 安い順", keywords: []string{"Tシャツ"}, attributes: []model.SearchAttribute{size("M")}, sort: "price_asc"},
+		{text: "売り切れ スニーカー 27cm", keywords: []string{"スニーカー"}, attributes: []model.SearchAttribute{size("27")}, status: "sold"},
+		{text: "ＮＩＫＥ　５，０００円以下", keywords: []string{"NIKE"}, maxPrice: 5000},
+
+		// 通貨の記号・単位がない数字は金額にしない
+		{text: "iPhone 15以下", keywords: []string{"iPhone", "15以下"}},
+		{text: "27-28", keywords: []string{"27-28"}},
+		{text: "3日以上", keywords: []string{"3日以上"}},
+
+		// 漢字1文字の色は熟語の一部に一致させない
+		{text: "面白い本", keywords: []string{"面白い本"}},
+		{text: "青森のりんご", keywords: []string{"青森のりんご"}},
+		{text: "赤ちゃん服", keywords: []string{"赤ちゃん服"}},
+		{text: "白菜", keywords: []string{"白菜"}},
+		{text: "赤と黒", attributes: []model.SearchAttribute{color("black"), color("red")}},
+		{text: "白黒 ボーダー", keywords: []string{"ボーダー"}, attributes: []model.SearchAttribute{color("white"), color("black")}},
+		{text: "白色のシャツ", keywords: []string{"シャツ"}, attributes: []model.SearchAttribute{color("white")}},
+		{text: "ブルー shred", keywords: []string{"shred"}, attributes: []model.SearchAttribute{color("blue")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.text, func(t *testing.T) {
+			q := ParseSearchQuery(tt.text)
+			if tt.keywords == nil {
+				tt.keywords = []string{}
+			}
+			if tt.attributes == nil {
+				tt.attributes = []model.SearchAttribute{}
+			}
+			if !reflect.DeepEqual(q.Keywords, tt.keywords) {
+				t.Errorf("Keywords = %q, want %q", q.Keywords, tt.keywords)
+			}
+			if !reflect.DeepEqual(q.Attributes, tt.attributes) {
+				t.Errorf("Attributes = %v, want %v", q.Attributes, tt.attributes)
+			}
+			if q.MinPrice != tt.minPrice || q.MaxPrice != tt.maxPrice {
+				t.Errorf("price = %d..%d, want %d..%d", q.MinPrice, q.MaxPrice, tt.minPrice, tt.maxPrice)
+			}
+			if q.Status != tt.status {
+				t.Errorf("Status = %q, want %q", q.Status, tt.status)
+			}
+			if q.Sort != tt.sort {
+				t.Errorf("Sort = %q, want %q", q.Sort, tt.sort)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"errors"
+	"time"
+
+	"hackathon-backend/dao"
+	"hackathon-backend/model"
+)
+
+// 分析できる最大の期間
+const maxAnalyticsDays = 366
+
+var ErrAnalyticsRangeTooLong = errors.New("range must be at most 366 days")
+
+type SellerAnalyticsUsecase struct {
+	AnalyticsDAO *dao.SellerAnalyticsDao
+	UserDAO      *dao.UserDao
+}
+
+func NewSellerAnalyticsUsecase(aDAO *dao.SellerAnalyticsDao, uDAO *dao.UserDao) *SellerAnalyticsUsecase {
+	return &SellerAnalyticsUsecase{
+		AnalyticsDAO: aDAO,
+		UserDAO:      uDAO,
+	}
+}
+
+// GetAnalytics: 自分の出品の [from, to) の閲覧・いいね・問い合わせ・成約をまとめる
+// 期間内に反応がなかった商品も含めます (出品直後の商品を比べられるように)
+func (u *SellerAnalyticsUsecase) GetAnalytics(firebaseUID string, from, to time.Time) (*model.SellerAnalytics, error) {
+	user, err := u.UserDAO.FindByFirebaseUID(firebaseUID)
+	if err != nil || user == nil {
+		return nil, errors.New("user not found")
+	}
+	if to.Sub(from) > maxAnalyticsDays*24*time.Hour {
+		return nil, ErrAnalyticsRangeTooLong
+	}
+
+	listings, err := u.AnalyticsDAO.ListingStats(user.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	events, err := u.AnalyticsDAO.DailyEvents(user.ID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &model.SellerAnalytics{From: from, To: to, Listings: []*model.ListingAnalytics{}}
+
+	// 日ごとの集計 (反応のない日も0で埋める)
+	days := make(map[string]*model.AnalyticsDay)
+	for d := from; d.Before(to); d = d.AddDate(0, 0, 1) {
+		key := d.Format(time.DateOnly)
+		day := &model.AnalyticsDay{
+			Date:  key,
+			Views: events[key][model.ProductEventView],
+			Likes: events[key][model.ProductEventLike],
+		}
+		days[key] = day
+		res.Daily = append(res.Daily, day)
+	}
+
+	for _, l := range listings {
+		l.LikeRate = rate(l.Likes, l.Views)
+		l.InquiryRate = rate(l.MessageThreads, l.Views)
+
+		res.Totals.Listings++
+		res.Totals.Views += l.Views
+		res.Totals.Likes += l.Likes
+		res.Totals.MessageThreads += l.MessageThreads
+		if l.SoldAt != nil && !l.SoldAt.Before(from) && l.SoldAt.Before(to) {
+			res.Totals.Sales++
+			if day, ok := days[l.SoldAt.In(from.Location()).Format(time.DateOnly)]; ok {
+				day.Sales++
+			}
+		}
+		res.Listings = append(res.Listings, l)
+	}
+	res.Totals.LikeRate = rate(res.Totals.Likes, res.Totals.Views)
+	res.Totals.InquiryRate = rate(res.Totals.MessageThreads, res.Totals.Views)
+	res.Totals.ConversionRate = rate(res.Totals.Sales, res.Totals.Views)
+	return res, nil
+}
+
+// 割合 (分母が0なら0)
+func rate(n, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(n) / float64(total)
+}
@@ -0,0 +1,267 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"log"
+	"sync"
+	"time"
+
+	"hackathon-backend/dao"
+	"hackathon-backend/model"
+	"hackathon-backend/service"
+)
+
+const (
+	// インデックスをDBから読み直す間隔 (他のインスタンスで登録された商品を取り込むため)
+	similarIndexReloadInterval = 10 * time.Minute
+	// 読み込みに失敗したときの再試行の間隔 (失敗が続くと倍々にし、reload の間隔で頭打ち)
+	similarIndexRetryInterval = 30 * time.Second
+	// 埋め込みを1つ作るときのタイムアウト
+	embedTimeout = 30 * time.Second
+	// 埋め込みがない商品を1回の補完でいくつまで作るか
+	embedBackfillBatch = 50
+	// 埋め込みの作成に失敗した商品を再び試すまでの時間 (失敗が続くと倍々にする)
+	embedRetryBase = 5 * time.Minute
+	embedRetryMax  = 24 * time.Hour
+	// 再試行の時刻をこれだけ過ぎても試されなかった失敗の記録は捨てる (削除・売却された商品など)
+	embedFailureTTL = 24 * time.Hour
+)
+
+// 埋め込みの作成に失敗した商品 (次に試してよい時刻)
+type embedFailure struct {
+	count   int
+	retryAt time.Time
+}
+
+type SimilarProductUsecase struct {
+	ProductDAO     *dao.ProductDao
+	EmbeddingDAO   *dao.ProductEmbeddingDao
+	UserDAO        *dao.UserDao
+	StorageService *service.StorageService
+	Embedder       service.EmbeddingService
+	Index          *service.VectorIndex
+	UsageUsecase   *AIUsageUsecase
+
+	mu       sync.Mutex
+	inFlight map[string]bool
+	failures map[string]embedFailure
+}
+
+func NewSimilarProductUsecase(pDAO *dao.ProductDao, eDAO *dao.ProductEmbeddingDao, uDAO *dao.UserDao, sService *service.StorageService, embedder service.EmbeddingService, usageUsecase *AIUsageUsecase) *SimilarProductUsecase {
+	return &SimilarProductUsecase{
+		ProductDAO:     pDAO,
+		EmbeddingDAO:   eDAO,
+		UserDAO:        uDAO,
+		StorageService: sService,
+		Embedder:       embedder,
+		Index:          service.NewVectorIndex(),
+		UsageUsecase:   usageUsecase,
+		inFlight:       make(map[string]bool),
+		failures:       make(map[string]embedFailure),
+	}
+}
+
+// IndexProduct: 商品名と説明文から埋め込みを作って保存し、インデックスに追加する
+// 埋め込みの呼び出しは出品者のAI使用量として記録します
+func (u *SimilarProductUsecase) IndexProduct(ctx context.Context, productID, sellerID, name, description string) error {
+	usageCtx, rec := service.WithUsageRecorder(ctx)
+	start := time.Now()
+	vec, err := u.Embedder.EmbedText(usageCtx, name+"\n"+description)
+	if recErr := u.UsageUsecase.Record(sellerID, "embed_product", rec.Usage(), time.Since(start), err != nil); recErr != nil {
+		log.Printf("similar products: failed to record usage for %s: %v", sellerID, recErr)
+	}
+	if err != nil {
+		return err
+	}
+
+	err = u.EmbeddingDAO.Upsert(&model.ProductEmbedding{
+		ProductID: productID,
+		Model:     u.Embedder.EmbeddingModel(),
+		Vector:    vec,
+	})
+	if err != nil {
+		return err
+	}
+	u.Index.Upsert(productID, vec)
+	return nil
+}
+
+// IndexProductAsync: IndexProduct をバックグラウンドで実行する (出品・更新のレスポンスを待たせない)
+// 失敗しても類似商品はキーワードで探せ、Run の補完で後から作り直すので、ログに残すだけにします
+func (u *SimilarProductUsecase) IndexProductAsync(productID, sellerID, name, description string) {
+	if !u.begin(productID, true) {
+		return
+	}
+	go u.indexTracked(productID, sellerID, name, description)
+}
+
+// Run: インデックスの読み込みと、埋め込みがない商品の補完を定期的に行います (ctx がキャンセルされるまで戻りません)
+// 読み込みに失敗したときは間隔を空けて再試行します
+func (u *SimilarProductUsecase) Run(ctx context.Context) {
+	wait := similarIndexRetryInterval
+	for {
+		u.pruneFailures(time.Now())
+
+		next := similarIndexReloadInterval
+		if err := u.loadIndex(); err != nil {
+			log.Printf("similar products: failed to load index: %v", err)
+			next = wait
+			wait = min(wait*2, similarIndexReloadInterval)
+		} else {
+			wait = similarIndexRetryInterval
+			u.backfill(ctx)
+		}
+
+		timer := time.NewTimer(next)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// DBからインデックスを読み直す
+func (u *SimilarProductUsecase) loadIndex() error {
+	// モデルが変わると次元や意味が違うので、今のモデルで作ったものだけを使う
+	embeddings, err := u.EmbeddingDAO.ListByModel(u.Embedder.EmbeddingModel())
+	if err != nil {
+		return err
+	}
+	vectors := make(map[string][]float32, len(embeddings))
+	for _, e := range embeddings {
+		vectors[e.ProductID] = e.Vector
+	}
+	u.Index.Replace(vectors)
+	return nil
+}
+
+// 埋め込みがない出品中の商品について埋め込みを作る (失敗して間もない商品は飛ばす)
+func (u *SimilarProductUsecase) backfill(ctx context.Context) {
+	products, err := u.EmbeddingDAO.ListMissing(u.Embedder.EmbeddingModel(), embedBackfillBatch)
+	if err != nil {
+		log.Printf("similar products: failed to list products to index: %v", err)
+		return
+	}
+	for _, p := range products {
+		if ctx.Err() != nil {
+			return
+		}
+		if !u.begin(p.ID, false) {
+			continue
+		}
+		u.indexTracked(p.ID, p.UserID, p.Name, p.Description)
+	}
+}
+
+// 再試行されないまま残った失敗の記録を捨てる
+// 出品中の商品は補完のたびに試されて retryAt が進むので、残るのは削除・売却などで補完の対象外になった商品だけです
+func (u *SimilarProductUsecase) pruneFailures(now time.Time) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	for id, f := range u.failures {
+		if now.After(f.retryAt.Add(embedFailureTTL)) {
+			delete(u.failures, id)
+		}
+	}
+}
+
+// 同じ商品の埋め込みを重ねて作らないよう、作成中として登録する
+// force が false なら、失敗して再試行の時刻になっていない商品も断ります
+func (u *SimilarProductUsecase) begin(productID string, force bool) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.inFlight[productID] {
+		return false
+	}
+	if f, ok := u.failures[productID]; ok && !force && time.Now().Before(f.retryAt) {
+		return false
+	}
+	u.inFlight[productID] = true
+	return true
+}
+
+// IndexProduct を実行し、結果を作成中・失敗の記録に反映する (begin の後に呼ぶ)
+func (u *SimilarProductUsecase) indexTracked(productID, sellerID, name, description string) {
+	ctx, cancel := context.WithTimeout(context.Background(), embedTimeout)
+	defer cancel()
+	err := u.IndexProduct(ctx, productID, sellerID, name, description)
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	delete(u.inFlight, productID)
+	if err == nil {
+		delete(u.failures, productID)
+		return
+	}
+	f := u.failures[productID]
+	f.count++
+	backoff := min(embedRetryBase<<(min(f.count, 10)-1), embedRetryMax)
+	f.retryAt = time.Now().Add(backoff)
+	u.failures[productID] = f
+	log.Printf("similar products: failed to index %s (attempt %d, retry in %s): %v", productID, f.count, backoff, err)
+}
+
+// FindSimilar: 出品中の商品から productID に似ているものを返す
+// 埋め込みがまだない商品はキーワードの一致で探します (埋め込みは出品時か Run の補完で作る)
+func (u *SimilarProductUsecase) FindSimilar(ctx context.Context, productID, viewerFirebaseUID string, limit int) (*model.SimilarProductsRes, error) {
+	if limit < 1 || limit > 50 {
+		limit = 12
+	}
+
+	currentUserID := ""
+	if viewerFirebaseUID != "" {
+		user, err := u.UserDAO.FindByFirebaseUID(viewerFirebaseUID)
+		if err == nil && user != nil {
+			currentUserID = user.ID
+		}
+	}
+
+	product, err := u.ProductDAO.FindByID(productID, currentUserID)
+	if err != nil {
+		return nil, err
+	}
+	if product.IsTakenDown {
+		return nil, sql.ErrNoRows
+	}
+
+	res := &model.SimilarProductsRes{Products: []*model.Product{}}
+	if vec, ok := u.Index.Get(productID); ok {
+		// 売り切れ・非表示の商品はDBで除かれるので多めに探す
+		matches := u.Index.Search(vec, limit*3, map[string]bool{productID: true})
+		ids := make([]string, len(matches))
+		for i, m := range matches {
+			ids[i] = m.ID
+		}
+		products, err := u.ProductDAO.FindSellingByIDs(ids, currentUserID)
+		if err != nil {
+			return nil, err
+		}
+		res.Source = model.SimilarSourceEmbedding
+		res.Products = products
+	} else {
+		products, err := u.ProductDAO.FindSellingByKeywords(splitSearchTerms(product.Name), productID, currentUserID, limit)
+		if err != nil {
+			return nil, err
+		}
+		res.Source = model.SimilarSourceKeyword
+		if products != nil {
+			res.Products = products
+		}
+	}
+
+	if len(res.Products) > limit {
+		res.Products = res.Products[:limit]
+	}
+	for _, p := range res.Products {
+		if p.ImageURL != "" {
+			signedURL, err := u.StorageService.GenerateSignedURL(p.ImageURL)
+			if err == nil {
+				p.ImageURL = signedURL
+			}
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,26 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSimilarProductPruneFailures(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	u := &SimilarProductUsecase{failures: map[string]embedFailure{
+		"waiting":   {count: 1, retryAt: now.Add(time.Hour)},
+		"due":       {count: 3, retryAt: now.Add(-time.Hour)},
+		"abandoned": {count: 9, retryAt: now.Add(-embedFailureTTL - time.Minute)},
+	}}
+
+	u.pruneFailures(now)
+
+	for _, id := range []string{"waiting", "due"} {
+		if _, ok := u.failures[id]; !ok {
+			t.Errorf("failure for %q was pruned, want kept", id)
+		}
+	}
+	if _, ok := u.failures["abandoned"]; ok {
+		t.Error(`failure for "abandoned" was kept, want pruned`)
+	}
+}
@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"hackathon-backend/dao"
+	"hackathon-backend/model"
+	"hackathon-backend/service"
+)
+
+const (
+	translationBatchSize      = 20 // 1回のLLM呼び出しで翻訳するメッセージ数
+	maxTranslationsPerRequest = 60 // 1リクエストで新しく翻訳する上限 (古いものは次回以降)
+)
+
+type TranslationUsecase struct {
+	TranslationDAO *dao.MessageTranslationDao
+	LLM            service.LLMService
+}
+
+func NewTranslationUsecase(tDAO *dao.MessageTranslationDao, llm service.LLMService) *TranslationUsecase {
+	return &TranslationUsecase{
+		TranslationDAO: tDAO,
+		LLM:            llm,
+	}
+}
+
+// TranslateMessages: 相手から届いたメッセージに language への翻訳を付ける
+// 翻訳結果はメッセージ・言語ごとに保存し、2回目以降は保存済みのものを使います
+// 翻訳に失敗しても履歴の表示は止めたくないので、LLMのエラーはログに残して翻訳なしで返します
+func (u *TranslationUsecase) TranslateMessages(ctx context.Context, msgs []*model.Message, viewerID, language string) error {
+	// 1. 翻訳対象 (相手からの、取り消されていないメッセージ)
+	var targets []*model.Message
+	var ids []string
+	for _, m := range msgs {
+		if m.SenderID == viewerID || m.IsDeleted || m.Content == "" {
+			continue
+		}
+		targets = append(targets, m)
+		ids = append(ids, m.ID)
+	}
+
+	// 2. 保存済みの翻訳を付ける
+	saved, err := u.TranslationDAO.FindByMessageIDs(ids, language)
+	if err != nil {
+		return err
+	}
+	var missing []*model.Message
+	for _, m := range targets {
+		if t, ok := saved[m.ID]; ok {
+			m.Translation = t
+		} else {
+			missing = append(missing, m)
+		}
+	}
+
+	// 3. 未翻訳のものを新しい順に優先して翻訳する
+	if len(missing) > maxTranslationsPerRequest {
+		missing = missing[len(missing)-maxTranslationsPerRequest:]
+	}
+	for start := 0; start < len(missing); start += translationBatchSize {
+		batch := missing[start:min(start+translationBatchSize, len(missing))]
+		if err := u.translateBatch(ctx, batch, language); err != nil {
+			log.Printf("translation: failed to translate %d messages into %s: %v", len(batch), language, err)
+			return nil
+		}
+	}
+	return nil
+}
+
+// 翻訳の出力スキーマ
+var translationSchema = &service.JSONSchema{
+	Type: "object",
+	Properties: map[string]*service.JSONSchema{
+		"translations": {Type: "array", Items: &service.JSONSchema{
+			Type: "object",
+			Properties: map[string]*service.JSONSchema{
+				"id":              {Type: "string"},
+				"source_language": {Type: "string", Description: "原文の言語 (ISO 639-1。例: ja, en, zh, ko)"},
+				"text":            {Type: "string"},
+			},
+			Required: []string{"id", "source_language", "text"},
+		}},
+	},
+	Required: []string{"translations"},
+}
+
+type translationInput struct {
+	ID   string `json:"id"`
+	Text string `json:"text"`
+}
+
+// まとめて翻訳して保存し、メッセージに付ける
+func (u *TranslationUsecase) translateBatch(ctx context.Context, batch []*model.Message, language string) error {
+	inputs := make([]translationInput, len(batch))
+	byID := make(map[string]*model.Message, len(batch))
+	for i, m := range batch {
+		inputs[i] = translationInput{ID: m.ID, Text: m.Content}
+		byID[m.ID] = m
+	}
+	inputJSON, err := json.Marshal(inputs)
+	if err != nil {
+		return err
+	}
+
+	prompt := fmt.Sprintf(`
+あなたはフリマアプリの取引チャットの翻訳者です。以下のメッセージをそれぞれ%sに翻訳してください。
+
+【メッセージ (JSON)】
+%s
+
+【条件】
+- 意味を変えず、自然な話し言葉で訳す
+- 商品名・固有名詞・金額はそのまま残す
+- すでに%sで書かれているメッセージは、原文をそのまま text に入れる
+- 各メッセージの id はそのまま返す
+`, languageNames[language], inputJSON, languageNames[language])
+
+	respText, err := u.LLM.GenerateJSON(ctx, prompt, translationSchema, nil, "")
+	if err != nil {
+		return err
+	}
+
+	var res struct {
+		Translations []struct {
+			ID             string `json:"id"`
+			SourceLanguage string `json:"source_language"`
+			Text           string `json:"text"`
+		} `json:"translations"`
+	}
+	if err := json.Unmarshal([]byte(respText), &res); err != nil {
+		return fmt.Errorf("failed to parse translation response: %w", err)
+	}
+
+	for _, r := range res.Translations {
+		m, ok := byID[r.ID]
+		if !ok || r.Text == "" {
+			continue
+		}
+		t := &model.MessageTranslation{
+			MessageID:      m.ID,
+			Language:       language,
+			SourceLanguage: r.SourceLanguage,
+			Text:           r.Text,
+		}
+		if err := u.TranslationDAO.Create(t); err != nil {
+			return err
+		}
+		m.Translation = t
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package usecase
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"hackathon-backend/dao"
+)
+
+const (
+	// 急上昇スコアに使う期間と半減期
+	trendingWindow   = 7 * 24 * time.Hour
+	trendingHalfLife = 24 * time.Hour
+	// いいねは閲覧より強い反応なので重くする
+	trendingViewWeight = 1.0
+	trendingLikeWeight = 5.0
+	// 閲覧・いいねの記録を残す期間
+	// 急上昇スコアには trendingWindow 分しか使わないが、出品者向けの分析で過去1年分を見られるようにする
+	productEventRetention = maxAnalyticsDays * 24 * time.Hour
+	// 古い記録を1回の DELETE で消す件数
+	productEventPruneBatch = 5000
+)
+
+type TrendingUsecase struct {
+	EventDAO *dao.ProductEventDao
+}
+
+func NewTrendingUsecase(eDAO *dao.ProductEventDao) *TrendingUsecase {
+	return &TrendingUsecase{EventDAO: eDAO}
+}
+
+// Refresh: 急上昇スコアを計算し直し、保存期間を過ぎた閲覧・いいねの記録を消す
+func (u *TrendingUsecase) Refresh() error {
+	n, err := u.EventDAO.RecomputeTrendingScores(trendingWindow, trendingHalfLife, trendingViewWeight, trendingLikeWeight)
+	if err != nil {
+		return err
+	}
+	log.Printf("trending: updated %d products", n)
+	return u.pruneEvents()
+}
+
+// 保存期間を過ぎた記録を少しずつ消す
+func (u *TrendingUsecase) pruneEvents() error {
+	var total int64
+	for {
+		n, err := u.EventDAO.DeleteOlderThan(productEventRetention, productEventPruneBatch)
+		if err != nil {
+			return err
+		}
+		total += n
+		if n < productEventPruneBatch {
+			break
+		}
+	}
+	if total > 0 {
+		log.Printf("trending: pruned %d old product events", total)
+	}
+	return nil
+}
+
+// Run: interval ごとに Refresh を実行します (ctx がキャンセルされるまで戻りません)
+// 複数のインスタンスで同時に動いても結果は同じです
+func (u *TrendingUsecase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := u.Refresh(); err != nil {
+			log.Printf("trending: failed to refresh scores: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
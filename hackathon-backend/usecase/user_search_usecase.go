@@ -1,16 +1,22 @@
 package usecase
 
 import (
+	"log"
+
 	"hackathon-backend/dao"
 	"hackathon-backend/model"
+
+	"github.com/oklog/ulid/v2"
 )
 
 type SearchUserUsecase struct {
-	UserDao *dao.UserDao
+	UserDao      *dao.UserDao
+	FollowDao    *dao.FollowDao
+	StatsUsecase *UserStatsUsecase
 }
 
-func NewSearchUserUsecase(d *dao.UserDao) *SearchUserUsecase {
-	return &SearchUserUsecase{UserDao: d}
+func NewSearchUserUsecase(d *dao.UserDao, fDAO *dao.FollowDao, statsUsecase *UserStatsUsecase) *SearchUserUsecase {
+	return &SearchUserUsecase{UserDao: d, FollowDao: fDAO, StatsUsecase: statsUsecase}
 }
 
 func (u *SearchUserUsecase) GetUserByFirebaseUID(firebaseUID string) (*model.User, error) {
@@ -20,3 +26,56 @@ func (u *SearchUserUsecase) GetUserByFirebaseUID(firebaseUID string) (*model.Use
 func (u *SearchUserUsecase) GetUserByID(id string) (*model.User, error) {
 	return u.UserDao.FindByID(id)
 }
+
+// GetUserProfile: 公開プロフィール (フォロー数・フォロワー数、取引などの実績と、閲覧者がフォローしているか)
+// idOrHandle はユーザーIDかハンドル (@ は付いていてもいなくてもよい)。ユーザーがいなければ nil を返します
+func (u *SearchUserUsecase) GetUserProfile(idOrHandle, viewerFirebaseUID string) (*model.UserProfile, error) {
+	user, err := findByIDOrHandle(u.UserDao, idOrHandle)
+	if err != nil || user == nil {
+		return nil, err
+	}
+
+	profile := &model.UserProfile{PublicUser: user.Public()}
+	if profile.FollowerCount, err = u.FollowDao.CountFollowers(user.ID); err != nil {
+		return nil, err
+	}
+	if profile.FollowingCount, err = u.FollowDao.CountFollowing(user.ID); err != nil {
+		return nil, err
+	}
+	// 実績は付加情報なので、集計に失敗してもプロフィールは返す
+	if profile.Stats, err = u.StatsUsecase.GetStats(user.ID); err != nil {
+		log.Printf("user profile: failed to get stats of %s: %v", user.ID, err)
+	}
+	if viewerFirebaseUID != "" {
+		viewer, err := u.UserDao.FindByFirebaseUID(viewerFirebaseUID)
+		if err == nil && viewer != nil && viewer.ID != user.ID {
+			if profile.IsFollowing, err = u.FollowDao.IsFollowing(viewer.ID, user.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return profile, nil
+}
+
+// SearchUsers: 名前・ハンドルでユーザーを探す (BANされたユーザー・退会したユーザーは除く)
+func (u *SearchUserUsecase) SearchUsers(q string, page, limit int) (*model.UserSummaryPage, error) {
+	page, limit = normalizePage(page, limit)
+
+	users, err := u.UserDao.Search(q, limit, (page-1)*limit)
+	if err != nil {
+		return nil, err
+	}
+	total, err := u.UserDao.SearchCount(q)
+	if err != nil {
+		return nil, err
+	}
+	return &model.UserSummaryPage{Users: users, Total: total}, nil
+}
+
+// ULIDとして読めればIDで、読めなければハンドルで探す (/users/{id} 以下のルートで共通)
+func findByIDOrHandle(uDAO *dao.UserDao, idOrHandle string) (*model.User, error) {
+	if _, err := ulid.ParseStrict(idOrHandle); err == nil {
+		return uDAO.FindByID(idOrHandle)
+	}
+	return uDAO.FindByHandle(model.NormalizeHandle(idOrHandle))
+}
@@ -0,0 +1,155 @@
+package usecase
+
+import (
+	"encoding/json"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"hackathon-backend/dao"
+	"hackathon-backend/model"
+	"hackathon-backend/service"
+
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	// この時間を過ぎた集計は、古いものを返しつつ裏で計算し直す
+	userStatsRefreshAfter = 1 * time.Hour
+	// この時間を過ぎた集計は捨てて、その場で計算する
+	userStatsTTL = 24 * time.Hour
+
+	// 返信時間は直近90日・最大2000件のメッセージから計算する
+	responseTimeWindow      = 90 * 24 * time.Hour
+	responseTimeMaxMessages = 2000
+	// 返信の実績がこれより少なければ返信時間を出さない
+	minResponseSamples = 3
+)
+
+type UserStatsUsecase struct {
+	StatsDAO *dao.UserStatsDao
+	Cache    service.Cache
+
+	mu         sync.Mutex
+	refreshing map[string]bool
+}
+
+func NewUserStatsUsecase(sDAO *dao.UserStatsDao, cache service.Cache) *UserStatsUsecase {
+	return &UserStatsUsecase{
+		StatsDAO:   sDAO,
+		Cache:      cache,
+		refreshing: make(map[string]bool),
+	}
+}
+
+// GetStats: ユーザーの公開の実績
+// キャッシュがあればそれを返し、userStatsRefreshAfter を過ぎていれば裏で計算し直します
+func (u *UserStatsUsecase) GetStats(userID string) (*model.UserStats, error) {
+	if stats, ok := u.load(userID); ok {
+		if time.Since(stats.ComputedAt) > userStatsRefreshAfter {
+			u.refreshAsync(userID)
+		}
+		return stats, nil
+	}
+	return u.refresh(userID)
+}
+
+// 集計してキャッシュに保存する
+func (u *UserStatsUsecase) refresh(userID string) (*model.UserStats, error) {
+	now := time.Now()
+	stats := &model.UserStats{ComputedAt: now}
+
+	var err error
+	if stats.ListedCount, stats.SoldCount, stats.BoughtCount, err = u.StatsDAO.ListingCounts(userID); err != nil {
+		return nil, err
+	}
+	if stats.LastActiveAt, err = u.StatsDAO.LastActiveAt(userID); err != nil {
+		return nil, err
+	}
+	timings, err := u.StatsDAO.MessageTimings(userID, now.Add(-responseTimeWindow), responseTimeMaxMessages)
+	if err != nil {
+		return nil, err
+	}
+	stats.ResponseTimeMinutes = typicalResponseMinutes(userID, timings)
+
+	// ユーザーIDはULIDなので、登録日時はIDから分かる
+	if id, err := ulid.Parse(userID); err == nil {
+		stats.MemberSince = ulid.Time(id.Time())
+	}
+
+	b, err := json.Marshal(stats)
+	if err != nil {
+		return nil, err
+	}
+	u.Cache.Set(userStatsCacheKey(userID), b, userStatsTTL)
+	return stats, nil
+}
+
+// 同じユーザーの計算し直しを重ねて走らせない
+func (u *UserStatsUsecase) refreshAsync(userID string) {
+	u.mu.Lock()
+	if u.refreshing[userID] {
+		u.mu.Unlock()
+		return
+	}
+	u.refreshing[userID] = true
+	u.mu.Unlock()
+
+	go func() {
+		defer func() {
+			u.mu.Lock()
+			delete(u.refreshing, userID)
+			u.mu.Unlock()
+		}()
+		if _, err := u.refresh(userID); err != nil {
+			log.Printf("user stats: failed to refresh %s: %v", userID, err)
+		}
+	}()
+}
+
+func (u *UserStatsUsecase) load(userID string) (*model.UserStats, bool) {
+	b, ok := u.Cache.Get(userStatsCacheKey(userID))
+	if !ok {
+		return nil, false
+	}
+	var stats model.UserStats
+	if err := json.Unmarshal(b, &stats); err != nil {
+		return nil, false
+	}
+	return &stats, true
+}
+
+func userStatsCacheKey(userID string) string {
+	return "user_stats:" + userID
+}
+
+// typicalResponseMinutes: 相手からのメッセージに返信するまでの時間の中央値 (分)
+// 相手ごとに、返信していないメッセージのうち最初のものから、自分が次に送るまでを1回と数えます
+func typicalResponseMinutes(userID string, timings []*model.MessageTiming) *int {
+	waitingSince := make(map[string]time.Time) // 相手ID → 返信待ちになった時刻
+	var samples []time.Duration
+	for _, m := range timings {
+		if m.SenderID == userID {
+			if since, ok := waitingSince[m.ReceiverID]; ok {
+				samples = append(samples, m.CreatedAt.Sub(since))
+				delete(waitingSince, m.ReceiverID)
+			}
+			continue
+		}
+		if _, ok := waitingSince[m.SenderID]; !ok {
+			waitingSince[m.SenderID] = m.CreatedAt
+		}
+	}
+	if len(samples) < minResponseSamples {
+		return nil
+	}
+
+	sort.Slice(samples, func(i, j int) bool { return samples[i] < samples[j] })
+	median := samples[len(samples)/2]
+	if len(samples)%2 == 0 {
+		median = (samples[len(samples)/2-1] + median) / 2
+	}
+	minutes := int(median.Round(time.Minute) / time.Minute)
+	return &minutes
+}
@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+
+	"hackathon-backend/model"
+)
+
+func TestTypicalResponseMinutes(t *testing.T) {
+	const me = "me"
+	base := time.Date(2025, 1, 1, 9, 0, 0, 0, time.UTC)
+	// at: base から min 分後のメッセージ
+	at := func(min int, from, to string) *model.MessageTiming {
+		return &model.MessageTiming{SenderID: from, ReceiverID: to, CreatedAt: base.Add(time.Duration(min) * time.Minute)}
+	}
+	intPtr := func(v int) *int { return &v }
+
+	tests := []struct {
+		name    string
+		timings []*model.MessageTiming
+		want    *int
+	}{
+		{
+			name:    "no messages",
+			timings: nil,
+			want:    nil,
+		},
+		{
+			name: "too few replies",
+			timings: []*model.MessageTiming{
+				at(0, "a", me), at(10, me, "a"),
+				at(20, "b", me), at(30, me, "b"),
+			},
+			want: nil,
+		},
+		{
+			name: "median of odd samples",
+			timings: []*model.MessageTiming{
+				at(0, "a", me), at(5, me, "a"),
+				at(10, "b", me), at(40, me, "b"),
+				at(50, "c", me), at(60, me, "c"),
+			},
+			want: intPtr(10),
+		},
+		{
+			name: "median of even samples",
+			timings: []*model.MessageTiming{
+				at(0, "a", me), at(10, me, "a"),
+				at(20, "b", me), at(40, me, "b"),
+				at(50, "c", me), at(80, me, "c"),
+				at(90, "d", me), at(130, me, "d"),
+			},
+			want: intPtr(25),
+		},
+		{
+			name: "waits from the first unanswered message",
+			timings: []*model.MessageTiming{
+				at(0, "a", me), at(5, "a", me), at(30, me, "a"), // 30分 (2通目からではない)
+				at(40, "b", me), at(70, me, "b"), at(75, me, "b"), // 続けて送った分は数えない
+				at(80, "c", me), at(110, me, "c"),
+			},
+			want: intPtr(30),
+		},
+		{
+			name: "messages I start are not replies",
+			timings: []*model.MessageTiming{
+				at(0, me, "a"), at(5, "a", me), at(25, me, "a"),
+				at(30, me, "b"), at(35, "b", me), at(55, me, "b"),
+				at(60, me, "c"), at(65, "c", me), at(85, me, "c"),
+			},
+			want: intPtr(20),
+		},
+		{
+			name: "unanswered messages are ignored",
+			timings: []*model.MessageTiming{
+				at(0, "a", me), at(15, me, "a"),
+				at(20, "b", me), at(35, me, "b"),
+				at(40, "c", me), at(55, me, "c"),
+				at(60, "d", me),
+			},
+			want: intPtr(15),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := typicalResponseMinutes(me, tt.timings)
+			switch {
+			case got == nil && tt.want == nil:
+			case got == nil || tt.want == nil:
+				t.Errorf("typicalResponseMinutes() = %v, want %v", got, tt.want)
+			case *got != *tt.want:
+				t.Errorf("typicalResponseMinutes() = %d, want %d", *got, *tt.want)
+			}
+		})
+	}
+}
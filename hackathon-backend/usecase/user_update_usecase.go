@@ -3,12 +3,19 @@ package usecase
 import (
 	"context"
 	"errors"
+	"fmt"
 	"hackathon-backend/dao"
 	"hackathon-backend/model"
 	"hackathon-backend/service"
+	"log"
 	"mime/multipart"
 )
 
+var (
+	ErrInvalidHandle = errors.New("invalid handle")
+	ErrHandleTaken   = errors.New("handle is already taken")
+)
+
 type UserUpdateUsecase struct {
 	UserDAO        *dao.UserDao
 	StorageService *service.StorageService
@@ -18,7 +25,8 @@ func NewUserUpdateUsecase(uDAO *dao.UserDao, sService *service.StorageService) *
 	return &UserUpdateUsecase{UserDAO: uDAO, StorageService: sService}
 }
 
-func (u *UserUpdateUsecase) UpdateUser(ctx context.Context, firebaseUID, name, bio string, imageFile *multipart.FileHeader) (*model.User, error) {
+// UpdateUser: プロフィールを更新する (preferredLanguage・handle が nil ならそれぞれ変更しない)
+func (u *UserUpdateUsecase) UpdateUser(ctx context.Context, firebaseUID, name, bio string, preferredLanguage, handle *string, imageFile *multipart.FileHeader) (*model.User, error) {
 	// 1. ユーザー特定
 	user, err := u.UserDAO.FindByFirebaseUID(firebaseUID)
 	if err != nil {
@@ -28,9 +36,17 @@ func (u *UserUpdateUsecase) UpdateUser(ctx context.Context, firebaseUID, name, b
 		return nil, errors.New("user not found")
 	}
 
-	// 2. 値を書き換え
+	// 2. 値を書き換え (ハンドルは画像のアップロード前に形式と空きを確かめる)
 	user.Name = name
 	user.Bio = bio
+	if preferredLanguage != nil {
+		user.PreferredLanguage = *preferredLanguage
+	}
+	if handle != nil {
+		if err := u.checkHandle(user, *handle); err != nil {
+			return nil, err
+		}
+	}
 
 	if imageFile != nil {
 		file, err := imageFile.Open()
@@ -47,10 +63,43 @@ func (u *UserUpdateUsecase) UpdateUser(ctx context.Context, firebaseUID, name, b
 		user.ImageURL = imageURL
 	}
 
-	// 3. 保存
-	if err := u.UserDAO.Update(user); err != nil {
+	// 3. 保存 (ハンドルも同じ UPDATE で変える)
+	ok, err := u.UserDAO.Update(user)
+	if err != nil {
 		return nil, err
 	}
+	if !ok {
+		// 確かめた後に他の人が同じハンドルを取った。アップロードした画像は使われないので消す
+		if imageFile != nil {
+			if err := u.StorageService.DeleteImage(ctx, user.ImageURL); err != nil {
+				log.Printf("user update: failed to delete unused image %s: %v", user.ImageURL, err)
+			}
+		}
+		return nil, ErrHandleTaken
+	}
 
 	return user, nil
 }
+
+// 新しいハンドルを確かめて user に入れる (空文字なら未設定に戻す)
+// 最終的な重複のチェックは保存時の一意キーで行います
+func (u *UserUpdateUsecase) checkHandle(user *model.User, handle string) error {
+	handle = model.NormalizeHandle(handle)
+	if handle == user.Handle {
+		return nil
+	}
+	if handle != "" {
+		if err := model.ValidateHandle(handle); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidHandle, err)
+		}
+		other, err := u.UserDAO.FindByHandle(handle)
+		if err != nil {
+			return err
+		}
+		if other != nil && other.ID != user.ID {
+			return ErrHandleTaken
+		}
+	}
+	user.Handle = handle
+	return nil
+}